@@ -5,12 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/xseman/openapi-generator/internal/codegen"
 	"github.com/xseman/openapi-generator/internal/config"
 	"github.com/xseman/openapi-generator/internal/generator"
 	"github.com/xseman/openapi-generator/internal/generator/typescript"
@@ -54,14 +56,58 @@ Example:
 }
 
 var (
-	inputSpec            string
+	inputSpecs           []string
+	mergeStrategy        string
 	outputDir            string
 	generatorName        string
 	configFile           string
 	templateDir          string
 	additionalProperties []string
 	skipValidation       bool
+	strictSpec           bool
 	verbose              bool
+	tagStrategy          string
+	filters              []string
+	excludeFilters       []string
+	globalProperties     []string
+	enumNameMappings     []string
+	normalizerRules      []string
+	overlays             []string
+
+	// Remote spec loading
+	authValues    []string
+	authHosts     []string
+	remoteTimeout time.Duration
+	specCacheDir  string
+	offline       bool
+
+	// Template context dumps
+	debugModels          bool
+	debugOperations      bool
+	debugSupportingFiles bool
+	debugDir             string
+
+	// Naming and package options
+	packageName     string
+	apiPackage      string
+	modelPackage    string
+	invokerPackage  string
+	modelNamePrefix string
+	modelNameSuffix string
+	apiNamePrefix   string
+	apiNameSuffix   string
+
+	// Bundle options
+	bundleOutput string
+	bundleMode   string
+	bundleFormat string
+
+	// Convert options
+	convertInput    string
+	convertOutput   string
+	convertTo       string
+	convertFormat   string
+	convertSortKeys bool
 )
 
 func init() {
@@ -69,16 +115,86 @@ func init() {
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(configHelpCmd)
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(bundleCmd)
+	rootCmd.AddCommand(convertCmd)
 
 	// Generate command flags
-	generateCmd.Flags().StringVarP(&inputSpec, "input-spec", "i", "", "OpenAPI spec file")
+	generateCmd.Flags().StringArrayVarP(&inputSpecs, "input-spec", "i", nil, "OpenAPI spec file or URL, repeatable to merge several specs")
+	generateCmd.Flags().StringVar(&mergeStrategy, "merge-strategy", "", "Conflicts between merged specs: fail (default), rename or keepFirst")
 	generateCmd.Flags().StringVarP(&outputDir, "output", "o", "", "Output directory")
 	generateCmd.Flags().StringVarP(&generatorName, "generator-name", "g", "", "Generator to use")
 	generateCmd.Flags().StringVarP(&configFile, "config", "c", "", "Configuration file (JSON/YAML)")
 	generateCmd.Flags().StringVarP(&templateDir, "template-dir", "t", "", "Custom template directory")
 	generateCmd.Flags().StringArrayVarP(&additionalProperties, "additional-properties", "p", nil, "Key=value")
 	generateCmd.Flags().BoolVar(&skipValidation, "skip-validate-spec", false, "Skip spec validation")
+	generateCmd.Flags().BoolVar(&strictSpec, "strict-spec", false, "Fail on constructs generated less precisely than the spec describes them")
 	generateCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
+	generateCmd.Flags().StringArrayVar(&filters, "filter", nil, "Only generate operations/models matching key:value1|value2 (keys: tag, path, method, operationId, vendorExtension, model)")
+	generateCmd.Flags().StringArrayVar(&excludeFilters, "exclude", nil, "Skip operations/models matching key:value1|value2")
+	generateCmd.Flags().StringArrayVar(&globalProperties, "global-property", nil, "Global properties, e.g. models=Pet:Order,apis=false,supportingFiles=runtime.ts")
+	generateCmd.Flags().StringArrayVar(&enumNameMappings, "enum-name-mappings", nil, "Constant names of enum values, e.g. in-progress=ACTIVE,-1=UNKNOWN")
+	generateCmd.Flags().StringArrayVar(&overlays, "overlay", nil, "OpenAPI Overlay file applied to the spec before parsing, repeatable")
+	addRemoteFlags(generateCmd)
+	generateCmd.Flags().StringArrayVar(&normalizerRules, "openapi-normalizer", nil, "Normalizer rules applied to the spec, e.g. SIMPLIFY_ONEOF_ANYOF=true")
+	generateCmd.Flags().BoolVar(&debugModels, "debug-models", false, "Dump the template context of every model file as JSON")
+	generateCmd.Flags().BoolVar(&debugOperations, "debug-operations", false, "Dump the template context of every API file as JSON")
+	generateCmd.Flags().BoolVar(&debugSupportingFiles, "debug-supporting-files", false, "Dump the template context of every supporting file as JSON")
+	generateCmd.Flags().StringVar(&debugDir, "debug-dir", "", "Write the template context dumps to this directory instead of stdout")
+	generateCmd.Flags().StringVar(&tagStrategy, "tag-strategy", "", "APIs of operations with several tags: first, all or single (default \"first\")")
+	generateCmd.Flags().StringVar(&packageName, "package-name", "", "Package name exposed to the templates")
+	generateCmd.Flags().StringVar(&apiPackage, "api-package", "", "Folder of the generated APIs (default \"apis\")")
+	generateCmd.Flags().StringVar(&modelPackage, "model-package", "", "Folder of the generated models (default \"models\")")
+	generateCmd.Flags().StringVar(&invokerPackage, "invoker-package", "", "Invoker package exposed to the templates")
+	generateCmd.Flags().StringVar(&modelNamePrefix, "model-name-prefix", "", "Prefix added to model names")
+	generateCmd.Flags().StringVar(&modelNameSuffix, "model-name-suffix", "", "Suffix added to model names")
+	generateCmd.Flags().StringVar(&apiNamePrefix, "api-name-prefix", "", "Prefix added to API class names")
+	generateCmd.Flags().StringVar(&apiNameSuffix, "api-name-suffix", "", "Suffix added to API class names (default \"Api\")")
+
+	// Bundle command flags
+	bundleCmd.Flags().StringArrayVarP(&inputSpecs, "input-spec", "i", nil, "OpenAPI spec file or URL, repeatable to merge several specs")
+	bundleCmd.Flags().StringVarP(&bundleOutput, "output", "o", "", "Output file (default stdout)")
+	bundleCmd.Flags().StringVar(&bundleMode, "mode", "", "bundle (external references into components, default) or dereference (inline all references)")
+	bundleCmd.Flags().StringVar(&bundleFormat, "format", "", "Output format: yaml or json (default from the output extension, else yaml)")
+	bundleCmd.Flags().StringVar(&mergeStrategy, "merge-strategy", "", "Conflicts between merged specs: fail (default), rename or keepFirst")
+	bundleCmd.Flags().StringArrayVar(&overlays, "overlay", nil, "OpenAPI Overlay file applied to the spec before parsing, repeatable")
+	bundleCmd.Flags().BoolVar(&skipValidation, "skip-validate-spec", false, "Skip spec validation")
+	addRemoteFlags(bundleCmd)
+
+	// Convert command flags
+	convertCmd.Flags().StringVarP(&convertInput, "input-spec", "i", "", "OpenAPI or Swagger spec file or URL")
+	convertCmd.Flags().StringVarP(&convertOutput, "output", "o", "", "Output file (default stdout)")
+	convertCmd.Flags().StringVar(&convertTo, "to", "3.0", "OpenAPI version to convert to")
+	convertCmd.Flags().StringVar(&convertFormat, "format", "", "Output format: yaml or json (default from the output extension, else yaml)")
+	convertCmd.Flags().BoolVar(&convertSortKeys, "sort-keys", false, "Sort object keys instead of keeping their order")
+	convertCmd.Flags().BoolVar(&skipValidation, "skip-validate-spec", false, "Skip spec validation")
+	addRemoteFlags(convertCmd)
+}
+
+// addRemoteFlags adds the flags for loading remote specs to a command.
+func addRemoteFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVarP(&authValues, "auth", "a", nil, "Headers for remote specs as URL-encoded name:value pairs, e.g. Authorization:Bearer%20token")
+	cmd.Flags().StringArrayVar(&authHosts, "auth-host", nil, "Also send the --auth headers to this host, besides the host of the spec")
+	cmd.Flags().DurationVar(&remoteTimeout, "remote-timeout", parser.DefaultRemoteTimeout, "Timeout of every request for a remote spec or reference")
+	cmd.Flags().StringVar(&specCacheDir, "spec-cache-dir", "", "Cache remote specs in this directory, revalidated by ETag")
+	cmd.Flags().BoolVar(&offline, "offline", false, "Load remote specs from --spec-cache-dir only")
+}
+
+// remoteOptions returns the options for loading remote specs.
+func remoteOptions() (parser.RemoteOptions, error) {
+	headers, err := parser.ParseAuth(authValues)
+	if err != nil {
+		return parser.RemoteOptions{}, err
+	}
+	if offline && specCacheDir == "" {
+		return parser.RemoteOptions{}, fmt.Errorf("--offline requires --spec-cache-dir")
+	}
+	return parser.RemoteOptions{
+		Headers:   headers,
+		AuthHosts: authHosts,
+		Timeout:   remoteTimeout,
+		CacheDir:  specCacheDir,
+		Offline:   offline,
+	}, nil
 }
 
 var listCmd = &cobra.Command{
@@ -119,16 +235,175 @@ var versionCmd = &cobra.Command{
 	},
 }
 
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Bundle a multi-file OpenAPI specification into one file",
+	Long: `Resolve the external references of an OpenAPI specification and write it
+as a single file. Swagger 2.0 specifications are converted to OpenAPI 3.
+
+Example:
+  openapi-generator bundle -i root.yaml -o bundled.yaml
+  openapi-generator bundle -i root.yaml -o bundled.json --mode dereference`,
+	RunE: runBundle,
+}
+
+func runBundle(cmd *cobra.Command, args []string) error {
+	if len(inputSpecs) == 0 {
+		return fmt.Errorf("input-spec is required (use -i flag)")
+	}
+
+	mode, err := parser.ParseBundleMode(bundleMode)
+	if err != nil {
+		return err
+	}
+	format := outputFormat(bundleFormat, bundleOutput)
+
+	p := parser.NewParser()
+	p.SkipValidation = skipValidation
+	if p.Remote, err = remoteOptions(); err != nil {
+		return err
+	}
+	if p.MergeStrategy, err = parser.ParseMergeStrategy(mergeStrategy); err != nil {
+		return err
+	}
+	for _, path := range overlays {
+		overlay, err := parser.LoadOverlay(path)
+		if err != nil {
+			return err
+		}
+		p.Overlays = append(p.Overlays, overlay)
+	}
+	if err := p.LoadSpecs(inputSpecs); err != nil {
+		return err
+	}
+
+	data, err := p.Bundle(mode, format)
+	if err != nil {
+		return err
+	}
+	return writeOutput(bundleOutput, data)
+}
+
+var convertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "Convert a specification to OpenAPI 3.0, JSON or YAML",
+	Long: `Convert a Swagger 2.0 specification to OpenAPI 3.0, with the conversion used
+for generation, or convert a specification between JSON and YAML.
+
+Example:
+  openapi-generator convert -i swagger.json -o openapi.yaml --to 3.0
+  openapi-generator convert -i openapi.yaml -o openapi.json --sort-keys`,
+	RunE: runConvert,
+}
+
+func runConvert(cmd *cobra.Command, args []string) error {
+	if convertInput == "" {
+		return fmt.Errorf("input-spec is required (use -i flag)")
+	}
+
+	p := parser.NewParser()
+	p.SkipValidation = skipValidation
+	remote, err := remoteOptions()
+	if err != nil {
+		return err
+	}
+	p.Remote = remote
+
+	data, err := p.Convert(convertInput, parser.ConvertOptions{
+		To:       convertTo,
+		Format:   outputFormat(convertFormat, convertOutput),
+		SortKeys: convertSortKeys,
+	})
+	if err != nil {
+		return err
+	}
+	return writeOutput(convertOutput, data)
+}
+
+// outputFormat returns the format of a written spec: the given one, or json
+// for a .json output file, else yaml.
+func outputFormat(format, output string) string {
+	if format != "" {
+		return format
+	}
+	if strings.EqualFold(filepath.Ext(output), ".json") {
+		return "json"
+	}
+	return "yaml"
+}
+
+// writeOutput writes a spec to a file, or to stdout without file.
+func writeOutput(output string, data []byte) error {
+	if output == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if dir := filepath.Dir(output); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	return nil
+}
+
 // Config represents the configuration file structure.
 // It mirrors the Java openapi-generator config format.
 type Config struct {
 	GeneratorName        string            `json:"generatorName" yaml:"generatorName"`
 	InputSpec            string            `json:"inputSpec" yaml:"inputSpec"`
+	InputSpecs           []string          `json:"inputSpecs" yaml:"inputSpecs"`
+	MergeStrategy        string            `json:"mergeStrategy" yaml:"mergeStrategy"`
 	OutputDir            string            `json:"outputDir" yaml:"outputDir"`
 	TemplateDir          string            `json:"templateDir" yaml:"templateDir"`
 	AdditionalProperties map[string]string `json:"additionalProperties" yaml:"additionalProperties"`
 	SkipValidation       bool              `json:"skipValidateSpec" yaml:"skipValidateSpec"`
+	StrictSpec           bool              `json:"strictSpec" yaml:"strictSpec"`
 	Verbose              bool              `json:"verbose" yaml:"verbose"`
+	TagStrategy          string            `json:"tagStrategy" yaml:"tagStrategy"`
+	Filter               []string          `json:"filter" yaml:"filter"`
+	Exclude              []string          `json:"exclude" yaml:"exclude"`
+	GlobalProperties     map[string]any    `json:"globalProperties" yaml:"globalProperties"`
+	EnumNameMappings     map[string]string `json:"enumNameMappings" yaml:"enumNameMappings"`
+	OpenapiNormalizer    map[string]string `json:"openapiNormalizer" yaml:"openapiNormalizer"`
+	Auth                 string            `json:"auth" yaml:"auth"`
+	AuthHosts            []string          `json:"authHosts" yaml:"authHosts"`
+	SpecCacheDir         string            `json:"specCacheDir" yaml:"specCacheDir"`
+	Offline              bool              `json:"offline" yaml:"offline"`
+	Overlays             []string          `json:"overlays" yaml:"overlays"`
+	PackageName          string            `json:"packageName" yaml:"packageName"`
+	ApiPackage           string            `json:"apiPackage" yaml:"apiPackage"`
+	ModelPackage         string            `json:"modelPackage" yaml:"modelPackage"`
+	InvokerPackage       string            `json:"invokerPackage" yaml:"invokerPackage"`
+	ModelNamePrefix      string            `json:"modelNamePrefix" yaml:"modelNamePrefix"`
+	ModelNameSuffix      string            `json:"modelNameSuffix" yaml:"modelNameSuffix"`
+	ApiNamePrefix        string            `json:"apiNamePrefix" yaml:"apiNamePrefix"`
+	ApiNameSuffix        string            `json:"apiNameSuffix" yaml:"apiNameSuffix"`
+}
+
+// reportDegradations lists the constructs generated less precisely than the
+// spec describes them, as errors in strict mode and as warnings with --verbose.
+// Otherwise only their number is printed.
+func reportDegradations(degradations []parser.Degradation, strict bool) error {
+	if len(degradations) == 0 {
+		return nil
+	}
+	switch {
+	case strict:
+		for _, d := range degradations {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", d)
+		}
+		return fmt.Errorf("strict spec: %d unsupported constructs", len(degradations))
+	case verbose:
+		for _, d := range degradations {
+			fmt.Printf("Warning: %s\n", d)
+		}
+	default:
+		fmt.Printf("Warning: %d constructs are generated less precisely than specified (use --verbose to list them)\n", len(degradations))
+	}
+	return nil
 }
 
 // loadConfigFile loads configuration from a JSON or YAML file.
@@ -164,6 +439,9 @@ func loadConfigFile(path string) (*Config, error) {
 }
 
 func runGenerate(cmd *cobra.Command, args []string) error {
+	globalProps := make(map[string]any)
+	enumNameMapping := make(map[string]string)
+
 	// Load config file if specified
 	if configFile != "" {
 		cfg, err := loadConfigFile(configFile)
@@ -172,8 +450,11 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		}
 
 		// Apply config values, CLI flags override config file
-		if inputSpec == "" && cfg.InputSpec != "" {
-			inputSpec = cfg.InputSpec
+		if len(inputSpecs) == 0 {
+			if cfg.InputSpec != "" {
+				inputSpecs = append(inputSpecs, cfg.InputSpec)
+			}
+			inputSpecs = append(inputSpecs, cfg.InputSpecs...)
 		}
 		if outputDir == "" && cfg.OutputDir != "" {
 			outputDir = cfg.OutputDir
@@ -187,9 +468,55 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		if cfg.SkipValidation {
 			skipValidation = true
 		}
+		if cfg.StrictSpec {
+			strictSpec = true
+		}
 		if cfg.Verbose {
 			verbose = true
 		}
+		if cfg.Offline {
+			offline = true
+		}
+		if len(authValues) == 0 && cfg.Auth != "" {
+			authValues = []string{cfg.Auth}
+		}
+		if len(authHosts) == 0 {
+			authHosts = cfg.AuthHosts
+		}
+		for _, opt := range []struct{ flag, value *string }{
+			{&packageName, &cfg.PackageName},
+			{&apiPackage, &cfg.ApiPackage},
+			{&modelPackage, &cfg.ModelPackage},
+			{&invokerPackage, &cfg.InvokerPackage},
+			{&modelNamePrefix, &cfg.ModelNamePrefix},
+			{&modelNameSuffix, &cfg.ModelNameSuffix},
+			{&apiNamePrefix, &cfg.ApiNamePrefix},
+			{&apiNameSuffix, &cfg.ApiNameSuffix},
+			{&tagStrategy, &cfg.TagStrategy},
+			{&mergeStrategy, &cfg.MergeStrategy},
+			{&specCacheDir, &cfg.SpecCacheDir},
+		} {
+			if *opt.flag == "" {
+				*opt.flag = *opt.value
+			}
+		}
+		for k, v := range cfg.GlobalProperties {
+			globalProps[k] = v
+		}
+		for value, name := range cfg.EnumNameMappings {
+			enumNameMapping[value] = name
+		}
+		// Normalizer rules of the CLI come last, so they override the config file
+		configRules := make([]string, 0, len(cfg.OpenapiNormalizer))
+		for rule, value := range cfg.OpenapiNormalizer {
+			configRules = append(configRules, rule+"="+value)
+		}
+		normalizerRules = append(configRules, normalizerRules...)
+		// Filters of the config file and CLI add up
+		filters = append(cfg.Filter, filters...)
+		excludeFilters = append(cfg.Exclude, excludeFilters...)
+		// Overlays of the config file are applied first
+		overlays = append(cfg.Overlays, overlays...)
 		// Merge additional properties from config (CLI takes precedence)
 		if cfg.AdditionalProperties != nil {
 			for k, v := range cfg.AdditionalProperties {
@@ -209,13 +536,13 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	}
 
 	if verbose {
-		fmt.Printf("Input spec: %s\n", inputSpec)
+		fmt.Printf("Input spec: %s\n", strings.Join(inputSpecs, ", "))
 		fmt.Printf("Output dir: %s\n", outputDir)
 		fmt.Printf("Generator: %s\n", generatorName)
 	}
 
 	// Validate required fields (after config file loading)
-	if inputSpec == "" {
+	if len(inputSpecs) == 0 {
 		return fmt.Errorf("input-spec is required (use -i flag or inputSpec in config file)")
 	}
 	if outputDir == "" {
@@ -233,13 +560,29 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	// Parse additional properties
 	additionalProps := parseAdditionalProperties(additionalProperties)
 
+	// Global properties of the CLI override those of the config file
+	for k, v := range config.ParseGlobalProperties(globalProperties) {
+		globalProps[k] = v
+	}
+
 	// Create generator configuration
 	cfg := &config.GeneratorConfig{
-		InputSpec:            inputSpec,
+		InputSpec:            inputSpecs[0],
+		InputSpecs:           inputSpecs,
 		OutputDir:            outputDir,
 		GeneratorName:        generatorName,
 		TemplateDir:          templateDir,
+		PackageName:          packageName,
+		ApiPackage:           apiPackage,
+		ModelPackage:         modelPackage,
+		InvokerPackage:       invokerPackage,
+		ModelNamePrefix:      modelNamePrefix,
+		ModelNameSuffix:      modelNameSuffix,
+		ApiNamePrefix:        apiNamePrefix,
+		ApiNameSuffix:        apiNameSuffix,
+		StrictSpec:           strictSpec,
 		AdditionalProperties: additionalProps,
+		GlobalProperties:     globalProps,
 	}
 
 	// Create TypeScript config from additional properties
@@ -250,6 +593,17 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	gen := typescript.NewFetchGenerator()
 	gen.SetConfig(cfg)
 	gen.TSConfig = tsConfig
+	// Mappings of the CLI come last, so they override the config file
+	cliMappings, err := config.ParseEnumNameMappings(enumNameMappings)
+	if err != nil {
+		return err
+	}
+	for value, name := range cliMappings {
+		enumNameMapping[value] = name
+	}
+	for value, name := range enumNameMapping {
+		gen.EnumNameMapping[value] = name
+	}
 
 	// Process options
 	if err := gen.ProcessOpts(); err != nil {
@@ -258,28 +612,70 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 
 	// Parse OpenAPI spec
 	if verbose {
-		fmt.Printf("Parsing OpenAPI spec: %s\n", inputSpec)
+		fmt.Printf("Parsing OpenAPI spec: %s\n", strings.Join(inputSpecs, ", "))
 	}
 
 	p := parser.NewParser()
 
 	// Set up type conversion functions
-	p.GetTypeFunc = gen.GetSchemaType
+	p.GetTypeFunc = gen.GetTypeDeclaration
+	p.TypeDeclarationFunc = gen.GetPropertyTypeDeclaration
+	p.IsPrimitiveFunc = gen.IsPrimitive
 	p.ToModelNameFunc = gen.ToModelName
 	p.ToVarNameFunc = gen.ToVarName
+	p.ToEnumVarNameFunc = gen.ToEnumVarName
 
 	// Set validation flag
 	p.SkipValidation = skipValidation
 
-	// Load spec
-	if strings.HasPrefix(inputSpec, "http://") || strings.HasPrefix(inputSpec, "https://") {
-		if err := p.LoadFromURL(inputSpec); err != nil {
-			return fmt.Errorf("failed to load spec from URL: %w", err)
-		}
-	} else {
-		if err := p.LoadFromFile(inputSpec); err != nil {
-			return fmt.Errorf("failed to load spec from file: %w", err)
+	// Locate degradations with line numbers when they are reported
+	p.TrackOrigins = verbose || cfg.StrictSpec
+
+	remote, err := remoteOptions()
+	if err != nil {
+		return err
+	}
+	p.Remote = remote
+
+	strategy, err := parser.ParseTagStrategy(tagStrategy)
+	if err != nil {
+		return err
+	}
+	p.TagStrategy = strategy
+
+	filter, err := parser.ParseFilter(filters, excludeFilters)
+	if err != nil {
+		return err
+	}
+	p.Filter = filter
+
+	rules, err := parser.ParseNormalizerRules(normalizerRules)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range overlays {
+		overlay, err := parser.LoadOverlay(path)
+		if err != nil {
+			return err
 		}
+		p.Overlays = append(p.Overlays, overlay)
+	}
+
+	merge, err := parser.ParseMergeStrategy(mergeStrategy)
+	if err != nil {
+		return err
+	}
+	p.MergeStrategy = merge
+
+	// Load the specs, merging them into one document
+	if err := p.LoadSpecs(inputSpecs); err != nil {
+		return err
+	}
+
+	// Rewrite the spec with the normalizer rules
+	if err := p.Normalize(rules); err != nil {
+		return err
 	}
 
 	// Get models and operations
@@ -293,24 +689,9 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get operations: %w", err)
 	}
 
-	// Detect and resolve operation ID conflicts within each tag
-	for tag, ops := range operationsByTag {
-		operationIDs := make(map[string]int)
-		for i := range ops {
-			opID := ops[i].OperationId
-			if count, exists := operationIDs[opID]; exists {
-				// Conflict detected - rename by appending suffix
-				suffix := count + 1
-				newID := fmt.Sprintf("%s%d", opID, suffix)
-				if verbose {
-					fmt.Printf("Warning: Duplicate operation ID '%s' in tag '%s', renaming to '%s'\n", opID, tag, newID)
-				}
-				ops[i].OperationId = newID
-				ops[i].Nickname = newID
-				operationIDs[opID] = suffix
-			} else {
-				operationIDs[opID] = 0
-			}
+	if verbose {
+		for _, warning := range p.Warnings {
+			fmt.Printf("Warning: %s\n", warning)
 		}
 	}
 
@@ -328,6 +709,10 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Found %d operations in %d tags\n", opCount, len(operationsByTag))
 	}
 
+	if err := reportDegradations(p.Degradations, cfg.StrictSpec); err != nil {
+		return err
+	}
+
 	// Post-process models
 	models = gen.PostProcessModels(models)
 
@@ -336,6 +721,15 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		operationsByTag[tag] = gen.PostProcessOperations(ops)
 	}
 
+	// Files to generate, from the models, apis and supportingFiles global properties
+	modelFiles, apiFiles, supportingFiles := cfg.FileSelections()
+
+	// Template context dumps, from the CLI or the debug global properties
+	dump := &templateDump{dir: debugDir}
+	debugModels = debugModels || cfg.GlobalSwitch(config.GlobalPropertyDebugModels)
+	debugOperations = debugOperations || cfg.GlobalSwitch(config.GlobalPropertyDebugOperations)
+	debugSupportingFiles = debugSupportingFiles || cfg.GlobalSwitch(config.GlobalPropertyDebugSupportingFiles)
+
 	// Set up template engine
 	tmplDir := templateDir
 	if tmplDir == "" {
@@ -373,20 +767,27 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	basePath := p.GetBasePath()
 
 	baseData := map[string]any{
-		"appName":          info["title"],
-		"appDescription":   info["description"],
-		"version":          info["version"],
-		"infoEmail":        info["infoEmail"],
-		"infoUrl":          info["infoUrl"],
-		"licenseName":      info["licenseName"],
-		"licenseUrl":       info["licenseUrl"],
-		"basePath":         basePath,
-		"host":             extractHost(basePath),
-		"generatorClass":   "TypeScriptFetchClientCodegen",
-		"generatorVersion": version,
-		"generatedDate":    time.Now().Format(time.RFC3339),
-		"apiPackage":       gen.ApiPackage,
-		"modelPackage":     gen.ModelPackage,
+		"appName":             info["title"],
+		"appDescription":      info["description"],
+		"version":             info["version"],
+		"infoEmail":           info["infoEmail"],
+		"infoUrl":             info["infoUrl"],
+		"licenseName":         info["licenseName"],
+		"licenseUrl":          info["licenseUrl"],
+		"basePath":            basePath,
+		"host":                extractHost(basePath),
+		"servers":             template.ConvertSliceToMaps(p.GetServers()),
+		"generatorClass":      "TypeScriptFetchClientCodegen",
+		"generatorVersion":    version,
+		"generatedDate":       time.Now().Format(time.RFC3339),
+		"apiPackage":          gen.ApiPackage,
+		"modelPackage":        gen.ModelPackage,
+		"packageName":         gen.PackageName,
+		"invokerPackage":      gen.InvokerPackage,
+		"apiFolder":           gen.ApiFolder(),
+		"modelFolder":         gen.ModelFolder(),
+		"apiRelativeToRoot":   typescript.RelativeToRoot(gen.ApiFolder()),
+		"modelRelativeToRoot": typescript.RelativeToRoot(gen.ModelFolder()),
 	}
 
 	// Merge additional properties
@@ -402,6 +803,10 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	// Track generated files for metadata
 	var generatedFiles []string
 
+	// Output folders of the models and APIs
+	modelDir := filepath.FromSlash(gen.ModelFolder())
+	apiDir := filepath.FromSlash(gen.ApiFolder())
+
 	// Generate supporting files
 	if verbose {
 		fmt.Println("Generating supporting files...")
@@ -410,8 +815,16 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	// Convert models to maps for template rendering and preprocess for Mustache compatibility
 	modelMaps := template.ConvertSliceToMaps(models)
 	modelMaps = template.PreprocessModelData(modelMaps)
+	for _, modelMap := range modelMaps {
+		addMappedModelFilenames(modelMap, gen)
+	}
 
 	for _, sf := range gen.GetSupportingFiles() {
+		relPath := filepath.Join(sf.Folder, sf.DestinationFilename)
+		if !supportingFiles.Includes(sf.DestinationFilename, filepath.ToSlash(relPath)) {
+			continue
+		}
+
 		data := copyMap(baseData)
 		data["models"] = modelMaps
 		data["hasModels"] = len(models) > 0
@@ -423,12 +836,17 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  %s\n", outputPath)
 		}
 
+		if debugSupportingFiles {
+			if err := dump.write(relPath, sf.TemplateFile, data); err != nil {
+				return err
+			}
+		}
+
 		if err := engine.RenderToFile(sf.TemplateFile, data, outputPath); err != nil {
 			return fmt.Errorf("failed to generate %s: %w", sf.DestinationFilename, err)
 		}
 
 		// Track generated file (relative to output dir)
-		relPath := filepath.Join(sf.Folder, sf.DestinationFilename)
 		if relPath != "" {
 			generatedFiles = append(generatedFiles, relPath)
 		}
@@ -441,17 +859,21 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 
 	modelTemplates := gen.GetModelTemplateFiles()
 	for i, model := range models {
+		if !modelFiles.Includes(model.SchemaName, model.Classname) {
+			continue
+		}
 		for tmplFile, ext := range modelTemplates {
 			data := copyMap(baseData)
 			modelMap := modelMaps[i]
 			data["model"] = modelMap
 			data["models"] = []map[string]any{{"model": modelMap}}
 			data["classname"] = model.Classname
-			// hasImports should be true if we have regular imports OR oneOf imports
-			data["hasImports"] = len(model.Imports) > 0 || len(model.OneOfModels) > 0
+			// hasImports should be true if we have regular imports OR oneOf/anyOf imports
+			data["hasImports"] = len(model.Imports) > 0 || len(model.OneOfModels) > 0 || len(model.AnyOfModels) > 0
 			data["tsImports"] = toTsImports(model.Imports, gen)
-			// Add oneOfImports with proper filename conversion (separate from oneOfModels string array)
+			// Add oneOfImports/anyOfImports with proper filename conversion (separate from the string arrays)
 			data["oneOfImports"] = toTsImports(model.OneOfModels, gen)
+			data["anyOfImports"] = toTsImports(model.AnyOfModels, gen)
 			// Skip importing Blob helpers if this model IS Blob (to avoid conflicts)
 			data["isNotBlobModel"] = model.Classname != "Blob"
 			// Add model-level properties at top level for template access
@@ -461,41 +883,36 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 				}
 			}
 
-			// For oneOf, create a joined string since mustache doesn't support -last
-			if oneOf, ok := modelMap["oneOf"]; ok {
-				if oneOfArray, isArray := oneOf.([]any); isArray && len(oneOfArray) > 0 {
-					parts := make([]string, 0, len(oneOfArray))
-					for _, item := range oneOfArray {
-						itemStr := fmt.Sprintf("%v", item)
-						// Don't convert primitive types - use them as-is
-						if isPrimitiveTypeTS(itemStr) {
-							parts = append(parts, itemStr)
-						} else {
-							typeName := gen.ToModelName(itemStr)
-							if typeName != "" {
-								parts = append(parts, typeName)
-							}
-						}
-					}
-					if len(parts) > 0 {
-						data["oneOfJoined"] = strings.Join(parts, " | ")
-					} else {
-						data["oneOfJoined"] = "any"
-					}
-				}
+			// For oneOf/anyOf, create a joined string since mustache doesn't support -last,
+			// excluding the type of an excludable not member
+			var not *codegen.CodegenProperty
+			if model.ComposedSchemas != nil && model.ComposedSchemas.ExcludesNot {
+				not = model.ComposedSchemas.Not
+			}
+			if oneOf, ok := modelMap["oneOf"].([]any); ok && len(oneOf) > 0 {
+				data["oneOfJoined"] = gen.ExcludeType(joinComposedTypes(oneOf, gen), not)
+			}
+			if anyOf, ok := modelMap["anyOf"].([]any); ok && len(anyOf) > 0 {
+				data["anyOfJoined"] = gen.ExcludeType(joinComposedTypes(anyOf, gen), not)
 			}
 
-			outputPath := filepath.Join(outputDir, gen.ModelPackage, gen.ToModelFilename(model.Classname)+ext)
+			outputPath := filepath.Join(outputDir, modelDir, gen.ToModelFilename(model.Classname)+ext)
 			if verbose {
 				fmt.Printf("  %s\n", outputPath)
 			}
 
+			if debugModels {
+				if err := dump.write(filepath.Join(modelDir, gen.ToModelFilename(model.Classname)+ext), tmplFile, data); err != nil {
+					return err
+				}
+			}
+
 			if err := engine.RenderToFile(tmplFile, data, outputPath); err != nil {
 				return fmt.Errorf("failed to generate model %s: %w", model.Classname, err)
 			}
 
 			// Track generated file (relative to output dir)
-			relPath := filepath.Join(gen.ModelPackage, gen.ToModelFilename(model.Classname)+ext)
+			relPath := filepath.Join(modelDir, gen.ToModelFilename(model.Classname)+ext)
 			generatedFiles = append(generatedFiles, relPath)
 		}
 	}
@@ -508,6 +925,9 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	apiTemplates := gen.GetApiTemplateFiles()
 	for tag, ops := range operationsByTag {
 		apiClassname := gen.ToApiName(tag)
+		if !apiFiles.Includes(tag, apiClassname) {
+			continue
+		}
 
 		// Convert operations to maps and preprocess for Mustache compatibility
 		opMaps := template.ConvertSliceToMaps(ops)
@@ -518,8 +938,10 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 			data["classname"] = apiClassname
 			data["classVarName"] = strings.ToLower(apiClassname[:1]) + apiClassname[1:]
 			data["operations"] = map[string]any{
-				"operation": opMaps,
-				"classname": apiClassname,
+				"operation":            opMaps,
+				"classname":            apiClassname,
+				"description":          p.TagDescription(tag),
+				"unescapedDescription": p.TagDescription(tag),
 			}
 			data["operation"] = opMaps
 
@@ -542,25 +964,32 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 			}
 			data["hasEnums"] = hasEnums
 
-			outputPath := filepath.Join(outputDir, gen.ApiPackage, gen.ToApiFilename(apiClassname)+ext)
+			outputPath := filepath.Join(outputDir, apiDir, gen.ToApiFilename(apiClassname)+ext)
 			if verbose {
 				fmt.Printf("  %s\n", outputPath)
 			}
 
+			if debugOperations {
+				if err := dump.write(filepath.Join(apiDir, gen.ToApiFilename(apiClassname)+ext), tmplFile, data); err != nil {
+					return err
+				}
+			}
+
 			if err := engine.RenderToFile(tmplFile, data, outputPath); err != nil {
 				return fmt.Errorf("failed to generate API %s: %w", apiClassname, err)
 			}
 
 			// Track generated file (relative to output dir)
-			relPath := filepath.Join(gen.ApiPackage, gen.ToApiFilename(apiClassname)+ext)
+			relPath := filepath.Join(apiDir, gen.ToApiFilename(apiClassname)+ext)
 			generatedFiles = append(generatedFiles, relPath)
 		}
 	}
 
 	// Generate index files
-	if len(models) > 0 {
+	modelIndexFile := path.Join(gen.ModelFolder(), "index.ts")
+	if len(models) > 0 && supportingFiles.Includes("index.ts", modelIndexFile) {
 		modelIndex := generateModelIndex(models, gen)
-		modelIndexPath := filepath.Join(outputDir, gen.ModelPackage, "index.ts")
+		modelIndexPath := filepath.Join(outputDir, modelDir, "index.ts")
 		// Ensure directory exists
 		if err := os.MkdirAll(filepath.Dir(modelIndexPath), 0755); err != nil {
 			return fmt.Errorf("failed to create model index directory: %w", err)
@@ -568,12 +997,13 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		if err := os.WriteFile(modelIndexPath, []byte(modelIndex), 0600); err != nil {
 			return fmt.Errorf("failed to write model index: %w", err)
 		}
-		generatedFiles = append(generatedFiles, filepath.Join(gen.ModelPackage, "index.ts"))
+		generatedFiles = append(generatedFiles, filepath.Join(modelDir, "index.ts"))
 	}
 
-	if len(operationsByTag) > 0 {
+	apiIndexFile := path.Join(gen.ApiFolder(), "index.ts")
+	if len(operationsByTag) > 0 && supportingFiles.Includes("index.ts", apiIndexFile) {
 		apiIndex := generateApiIndex(operationsByTag, gen)
-		apiIndexPath := filepath.Join(outputDir, gen.ApiPackage, "index.ts")
+		apiIndexPath := filepath.Join(outputDir, apiDir, "index.ts")
 		// Ensure directory exists
 		if err := os.MkdirAll(filepath.Dir(apiIndexPath), 0755); err != nil {
 			return fmt.Errorf("failed to create API index directory: %w", err)
@@ -581,12 +1011,14 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		if err := os.WriteFile(apiIndexPath, []byte(apiIndex), 0600); err != nil {
 			return fmt.Errorf("failed to write API index: %w", err)
 		}
-		generatedFiles = append(generatedFiles, filepath.Join(gen.ApiPackage, "index.ts"))
+		generatedFiles = append(generatedFiles, filepath.Join(apiDir, "index.ts"))
 	}
 
-	// Generate .openapi-generator metadata
-	if err := generateMetadata(outputDir, generatedFiles, version); err != nil {
-		return fmt.Errorf("failed to generate metadata: %w", err)
+	// Generate .openapi-generator metadata, unless only part of the output was generated
+	if !modelFiles.IsRestricted() && !apiFiles.IsRestricted() && !supportingFiles.IsRestricted() {
+		if err := generateMetadata(outputDir, generatedFiles, version); err != nil {
+			return fmt.Errorf("failed to generate metadata: %w", err)
+		}
 	}
 
 	fmt.Printf("\nGeneration complete! Output written to: %s\n", outputDir)
@@ -639,6 +1071,9 @@ func applyAdditionalProperties(tsConfig *config.TypeScriptFetchConfig, props map
 	if v, ok := props["fileNaming"].(string); ok {
 		tsConfig.FileNaming = v
 	}
+	if v, ok := props["enumPropertyNaming"].(string); ok {
+		tsConfig.EnumPropertyNaming = v
+	}
 	if v, ok := props["validationAttributes"].(bool); ok {
 		tsConfig.GenerateValidationAttributes = v
 	}
@@ -691,7 +1126,8 @@ func copyMap(m map[string]any) map[string]any {
 func toTsImports(imports []string, gen *typescript.FetchGenerator) []map[string]string {
 	result := make([]map[string]string, 0, len(imports))
 	for _, imp := range imports {
-		className := gen.ToModelName(imp)
+		// Imports are model class names already, converting them again would repeat prefixes
+		className := imp
 		// Skip empty class names and primitive types
 		if className == "" || gen.IsPrimitive(className) {
 			continue
@@ -704,6 +1140,45 @@ func toTsImports(imports []string, gen *typescript.FetchGenerator) []map[string]
 	return result
 }
 
+// joinComposedTypes joins the member types of a oneOf/anyOf model into a union type.
+func joinComposedTypes(members []any, gen *typescript.FetchGenerator) string {
+	parts := make([]string, 0, len(members))
+	for _, item := range members {
+		itemStr := fmt.Sprintf("%v", item)
+		// Don't convert primitive types - use them as-is
+		if isPrimitiveTypeTS(itemStr) {
+			parts = append(parts, itemStr)
+		} else if itemStr != "" {
+			// Member types are model class names already
+			parts = append(parts, itemStr)
+		}
+	}
+	if len(parts) == 0 {
+		return "any"
+	}
+	return strings.Join(parts, " | ")
+}
+
+// addMappedModelFilenames adds the file name of every discriminator mapped model,
+// so templates can import them with the configured file naming.
+func addMappedModelFilenames(modelMap map[string]any, gen *typescript.FetchGenerator) {
+	discriminator, ok := modelMap["discriminator"].(map[string]any)
+	if !ok {
+		return
+	}
+	mappedModels, ok := discriminator["mappedModels"].([]any)
+	if !ok {
+		return
+	}
+	for _, item := range mappedModels {
+		if mm, ok := item.(map[string]any); ok {
+			if modelName, ok := mm["modelName"].(string); ok {
+				mm["filename"] = gen.ToModelFilename(modelName)
+			}
+		}
+	}
+}
+
 // isPrimitiveTypeTS checks if a type string is a TypeScript primitive type.
 // Returns true for built-in types like string, number, boolean, etc.
 func isPrimitiveTypeTS(t string) bool {
@@ -728,8 +1203,8 @@ func collectApiImports(ops []*generator.CodegenOperation, gen generator.CodegenC
 
 	result := make([]map[string]string, 0, len(imports))
 	for imp := range imports {
-		// Convert the import name to the proper model class name
-		className := gen.ToModelName(imp)
+		// Imports are model class names already, converting them again would repeat prefixes
+		className := imp
 		// Skip empty class names and primitive types
 		if className == "" || primitives[className] {
 			continue
@@ -765,7 +1240,7 @@ func generateModelIndex(models []*generator.CodegenModel, gen *typescript.FetchG
 	sb.WriteString("    BlobFromJSON,\n")
 	sb.WriteString("    BlobToJSON,\n")
 	sb.WriteString("    FromJSON,\n")
-	sb.WriteString("} from '../runtime';\n")
+	fmt.Fprintf(&sb, "} from '%sruntime';\n", typescript.RelativeToRoot(gen.ModelFolder()))
 	sb.WriteString("\n")
 
 	// Add ModelObject type for generic object schemas
@@ -831,11 +1306,51 @@ func printTypeScriptFetchConfigHelp() {
 	fmt.Println("      File naming convention: PascalCase, camelCase, kebab-case.")
 	fmt.Println("      (Default: kebab-case)")
 	fmt.Println()
+	fmt.Println("  enumPropertyNaming")
+	fmt.Println("      Enum constant naming: UPPERCASE, original, camelCase, PascalCase, snake_case.")
+	fmt.Println("      (Default: UPPERCASE)")
+	fmt.Println()
 	fmt.Println("  validationAttributes")
 	fmt.Println("      Generate validation metadata. (Default: false)")
 	fmt.Println()
 }
 
+// templateDump writes the template context of generated files as indented JSON,
+// so template authors can see the exact data a template is rendered with.
+type templateDump struct {
+	// Directory receiving one <file>.json per generated file, stdout when empty
+	dir string
+}
+
+// write dumps the context a template is rendered with for a file relative to the output directory.
+func (d *templateDump) write(relPath, templateFile string, data map[string]any) error {
+	if d.dir == "" {
+		out, err := json.MarshalIndent(map[string]any{
+			"file":     filepath.ToSlash(relPath),
+			"template": templateFile,
+			"context":  data,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to dump template context of %s: %w", relPath, err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	out, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to dump template context of %s: %w", relPath, err)
+	}
+	dumpPath := filepath.Join(d.dir, relPath+".json")
+	if err := os.MkdirAll(filepath.Dir(dumpPath), 0755); err != nil {
+		return fmt.Errorf("failed to create debug directory: %w", err)
+	}
+	if err := os.WriteFile(dumpPath, append(out, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dumpPath, err)
+	}
+	return nil
+}
+
 // generateMetadata creates the .openapi-generator folder with FILES and VERSION
 func generateMetadata(outputDir string, generatedFiles []string, version string) error {
 	metaDir := filepath.Join(outputDir, ".openapi-generator")
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_templateDump_write_directory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "debug")
+	dump := &templateDump{dir: dir}
+	data := map[string]any{"classname": "Pet"}
+	if err := dump.write(filepath.Join("models", "Pet.ts"), "models.mustache", data); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	path := filepath.Join(dir, "models", "Pet.ts.json")
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("dump is not JSON: %v", err)
+	}
+	if !reflect.DeepEqual(got, data) {
+		t.Errorf("dump = %v, want the bare context %v", got, data)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("dump mode = %v, %v", info.Mode().Perm(), err)
+	}
+}
+
+func Test_templateDump_write_stdout(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	writeErr := (&templateDump{}).write(filepath.Join("apis", "PetApi.ts"), "apis.mustache", map[string]any{"baseName": "pet"})
+	w.Close()
+	os.Stdout = stdout
+	if writeErr != nil {
+		t.Fatalf("write: %v", writeErr)
+	}
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("dump is not JSON: %v\n%s", err, raw)
+	}
+	want := map[string]any{
+		"file":     "apis/PetApi.ts",
+		"template": "apis.mustache",
+		"context":  map[string]any{"baseName": "pet"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dump = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,66 @@
+package codegen
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// StringLiteralEscaper escapes a string for a single-quoted literal.
+var StringLiteralEscaper = strings.NewReplacer(`\`, `\\`, "'", `\'`)
+
+var (
+	enumSeparatorPattern = regexp.MustCompile(`[ .-]`)
+	nonWordPattern       = regexp.MustCompile(`\W`)
+)
+
+// EnumLiteral returns an enum value as the spec writes it: null, strings
+// unquoted and numbers in their integer or decimal form.
+func EnumLiteral(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// EnumWords returns the words the constant of an enum value is named after:
+// its literal, "empty" for an empty string, or e.g. "minus 1" for -1.
+func EnumWords(value any) string {
+	literal := EnumLiteral(value)
+	switch {
+	case literal == "":
+		return "empty"
+	case strings.HasPrefix(literal, "-"):
+		if _, isNumber := value.(float64); isNumber {
+			return "minus " + literal[1:]
+		}
+	}
+	return literal
+}
+
+// EnumVarName returns the default constant name of an enum value, e.g.
+// IN_PROGRESS for "in-progress", MINUS_1 for -1, EMPTY for "" and NULL for null.
+func EnumVarName(value any) string {
+	name := strings.ToUpper(strings.ReplaceAll(EnumWords(value), "+", "PLUS"))
+	name = enumSeparatorPattern.ReplaceAllString(name, "_")
+	return EnumIdentifier(nonWordPattern.ReplaceAllString(name, ""))
+}
+
+// EnumIdentifier makes an enum constant name a valid identifier: names
+// starting with a digit get an underscore, and empty names become VALUE.
+func EnumIdentifier(name string) string {
+	if name == "" {
+		return "VALUE"
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		return "_" + name
+	}
+	return name
+}
@@ -0,0 +1,47 @@
+package codegen
+
+import "testing"
+
+func Test_EnumLiteral(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{nil, "null"},
+		{"it's", "it's"},
+		{float64(3), "3"},
+		{-1.5, "-1.5"},
+		{1e21, "1000000000000000000000"},
+		{true, "true"},
+	}
+	for _, tt := range tests {
+		if got := EnumLiteral(tt.value); got != tt.want {
+			t.Errorf("EnumLiteral(%#v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func Test_EnumVarName(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{"in-progress", "IN_PROGRESS"},
+		{"a.b c", "A_B_C"},
+		{"C++", "CPLUSPLUS"},
+		{"it's", "ITS"},
+		{"", "EMPTY"},
+		{"-1", "_1"},
+		{"2fa", "_2FA"},
+		{"€", "VALUE"},
+		{nil, "NULL"},
+		{float64(-1), "MINUS_1"},
+		{2.5, "_2_5"},
+		{true, "TRUE"},
+	}
+	for _, tt := range tests {
+		if got := EnumVarName(tt.value); got != tt.want {
+			t.Errorf("EnumVarName(%#v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
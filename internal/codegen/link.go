@@ -0,0 +1,52 @@
+package codegen
+
+// CodegenLink represents an OpenAPI link from a response to a follow-up operation.
+// Java openapi-generator has no equivalent; it is modeled after the Link Object.
+type CodegenLink struct {
+	Name         string `json:"name"`
+	OperationId  string `json:"operationId"`  // Target operationId (as written in the spec)
+	OperationRef string `json:"operationRef"` // Target operation reference (alternative to operationId)
+	Description  string `json:"description"`
+
+	// Resolved target operation (empty when the target cannot be found)
+	TargetPath       string `json:"targetPath"`
+	TargetHttpMethod string `json:"targetHttpMethod"`
+	TargetNickname   string `json:"targetNickname"`
+	TargetBaseName   string `json:"targetBaseName"` // Tag of the target operation
+	IsResolved       bool   `json:"isResolved"`
+
+	// Values passed to the target operation
+	Parameters    []*CodegenLinkParameter `json:"parameters"`
+	HasParameters bool                    `json:"hasParameters"`
+	RequestBody   *CodegenLinkParameter   `json:"requestBody"`
+
+	// Server override for the target operation
+	Server *CodegenServer `json:"server"`
+
+	// Vendor extensions
+	VendorExtensions map[string]any `json:"vendorExtensions"`
+}
+
+// CodegenLinkParameter represents a single value passed along a link.
+// Runtime expressions such as "$response.body#/id" are split into their parts
+// so templates can build accessors without parsing the expression themselves.
+type CodegenLinkParameter struct {
+	Name       string `json:"name"`       // Target parameter name
+	Expression string `json:"expression"` // Raw runtime expression or constant value
+
+	// Expression parts
+	Source          string   `json:"source"`          // "url", "method", "statusCode", "request" or "response"
+	Location        string   `json:"location"`        // "path", "query", "header" or "body"
+	Reference       string   `json:"reference"`       // Parameter or header name for non-body locations
+	Pointer         string   `json:"pointer"`         // JSON pointer for body locations (e.g. "/id")
+	PointerSegments []string `json:"pointerSegments"` // Unescaped JSON pointer segments
+
+	// Flags
+	IsConstant       bool `json:"isConstant"`
+	IsRequest        bool `json:"isRequest"`
+	IsResponse       bool `json:"isResponse"`
+	IsBody           bool `json:"isBody"`
+	IsHeader         bool `json:"isHeader"`
+	IsPathParameter  bool `json:"isPathParameter"`
+	IsQueryParameter bool `json:"isQueryParameter"`
+}
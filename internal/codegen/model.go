@@ -27,6 +27,10 @@ type CodegenModel struct {
 	IsNullable     bool   `json:"isNullable"`
 	IsDeprecated   bool   `json:"isDeprecated"`
 
+	// Reference analysis
+	IsSelfReference     bool `json:"isSelfReference"`     // References itself directly
+	IsCircularReference bool `json:"isCircularReference"` // Part of a reference cycle
+
 	// Primitive type flags
 	IsString         bool `json:"isString"`
 	IsInteger        bool `json:"isInteger"`
@@ -54,6 +58,7 @@ type CodegenModel struct {
 	OneOf           []string                `json:"oneOf"`       // Set of oneOf schema names
 	OneOfModels     []string                `json:"oneOfModels"` // Non-primitive oneOf members for imports
 	AnyOf           []string                `json:"anyOf"`       // Set of anyOf schema names
+	AnyOfModels     []string                `json:"anyOfModels"` // Non-primitive anyOf members for imports
 	AllOf           []string                `json:"allOf"`       // Set of allOf schema names
 	ComposedSchemas *CodegenComposedSchemas `json:"composedSchemas"`
 
@@ -80,13 +85,16 @@ type CodegenModel struct {
 	HasChildren     bool     `json:"hasChildren"`
 	HasMoreModels   bool     `json:"hasMoreModels"`
 	HasOneOf        bool     `json:"hasOneOf"`
+	HasAnyOf        bool     `json:"hasAnyOf"`
 
 	// Enum support
 	AllowableValues map[string]any `json:"allowableValues"` // {"values": [...]}
 
 	// Discriminator
-	Discriminator                       *CodegenDiscriminator `json:"discriminator"`
-	HasDiscriminatorWithNonEmptyMapping bool                  `json:"hasDiscriminatorWithNonEmptyMapping"`
+	Discriminator                          *CodegenDiscriminator `json:"discriminator"`
+	HasDiscriminatorWithNonEmptyMapping    bool                  `json:"hasDiscriminatorWithNonEmptyMapping"`
+	HasSelfReferencingDiscriminatorMapping bool                  `json:"hasSelfReferencingDiscriminatorMapping"`
+	SelfReferencingDiscriminatorMapping    *MappedModel          `json:"selfReferencingDiscriminatorMapping"` // Mapping to the model itself
 
 	// Additional properties
 	AdditionalPropertiesType   string           `json:"additionalPropertiesType"`
@@ -130,6 +138,9 @@ type CodegenModel struct {
 
 	// JSON representation
 	ModelJson string `json:"modelJson"`
+
+	// Location in the spec
+	Source *Source `json:"source,omitempty"`
 }
 
 // CodegenComposedSchemas holds composed schema references
@@ -138,4 +149,6 @@ type CodegenComposedSchemas struct {
 	OneOf []*CodegenProperty `json:"oneOf"`
 	AnyOf []*CodegenProperty `json:"anyOf"`
 	Not   *CodegenProperty   `json:"not"`
+
+	ExcludesNot bool `json:"excludesNot"` // Not is generated as an exclusion from the oneOf/anyOf union
 }
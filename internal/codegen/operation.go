@@ -68,17 +68,22 @@ type CodegenOperation struct {
 	IsResponseOptional     bool `json:"isResponseOptional"`
 	HasReference           bool `json:"hasReference"`
 	HasErrorResponseObject bool `json:"hasErrorResponseObject"`
+	HasLinks               bool `json:"hasLinks"`
 	UniqueItems            bool `json:"uniqueItems"`
 	SubresourceOperation   bool `json:"subresourceOperation"`
 
 	// Security
-	AuthMethods []*CodegenSecurity `json:"authMethods"`
+	AuthMethods          []*CodegenSecurity            `json:"authMethods"`          // Union of all schemes the operation accepts
+	SecurityRequirements []*CodegenSecurityRequirement `json:"securityRequirements"` // Alternatives, each a set of schemes
+	IsAuthOptional       bool                          `json:"isAuthOptional"`       // One alternative requires no authentication
+	IsSecurityInherited  bool                          `json:"isSecurityInherited"`  // Security comes from the document level
 
 	// Servers
 	Servers []*CodegenServer `json:"servers"`
 
 	// Callbacks
-	Callbacks []*CodegenCallback `json:"callbacks"`
+	Callbacks    []*CodegenCallback `json:"callbacks"`
+	HasCallbacks bool               `json:"hasCallbacks"`
 
 	// Examples
 	Examples            []map[string]string `json:"examples"`
@@ -98,17 +103,40 @@ type CodegenOperation struct {
 
 	// External docs
 	ExternalDocs map[string]any `json:"externalDocs"`
+
+	// Location in the spec
+	Source *Source `json:"source,omitempty"`
 }
 
 // CodegenServer represents server configuration
 type CodegenServer struct {
-	URL         string         `json:"url"`
-	Description string         `json:"description"`
-	Variables   map[string]any `json:"variables"`
+	URL          string                   `json:"url"` // URL template, may contain {variables}
+	Description  string                   `json:"description"`
+	Variables    []*CodegenServerVariable `json:"variables"`
+	HasVariables bool                     `json:"hasVariables"`
+
+	// Vendor extensions
+	VendorExtensions map[string]any `json:"vendorExtensions"`
+}
+
+// CodegenServerVariable represents a variable for server URL template substitution
+type CodegenServerVariable struct {
+	Name          string   `json:"name"`
+	Description   string   `json:"description"`
+	DefaultValue  string   `json:"defaultValue"`
+	EnumValues    []string `json:"enumValues"`
+	HasEnumValues bool     `json:"hasEnumValues"`
+
+	// Vendor extensions
+	VendorExtensions map[string]any `json:"vendorExtensions"`
 }
 
 // CodegenCallback represents a callback
 type CodegenCallback struct {
 	Name       string              `json:"name"`
-	Operations []*CodegenOperation `json:"operations"`
+	Classname  string              `json:"classname"`  // Handler type, e.g. CreatePetOnCreatedCallback
+	Operations []*CodegenOperation `json:"operations"` // Callback requests, Path holds the runtime expression
+
+	// Vendor extensions
+	VendorExtensions map[string]any `json:"vendorExtensions"`
 }
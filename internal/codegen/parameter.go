@@ -121,6 +121,9 @@ type CodegenParameter struct {
 	// MinProperties and MaxProperties
 	MinProperties *int `json:"minProperties"`
 	MaxProperties *int `json:"maxProperties"`
+
+	// Location in the spec
+	Source *Source `json:"source,omitempty"`
 }
 
 // CodegenMediaType represents media type content
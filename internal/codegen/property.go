@@ -129,4 +129,7 @@ type CodegenProperty struct {
 
 	// Composition
 	ComposedSchemas *CodegenComposedSchemas `json:"composedSchemas"`
+
+	// Location in the spec
+	Source *Source `json:"source,omitempty"`
 }
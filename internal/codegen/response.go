@@ -50,6 +50,10 @@ type CodegenResponse struct {
 	// Content
 	Content map[string]*CodegenMediaType `json:"content"`
 
+	// Links to follow-up operations
+	Links    []*CodegenLink `json:"links"`
+	HasLinks bool           `json:"hasLinks"`
+
 	// Nested
 	Items                *CodegenProperty   `json:"items"`
 	AdditionalProperties *CodegenProperty   `json:"additionalProperties"`
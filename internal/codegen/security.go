@@ -44,3 +44,12 @@ type CodegenSecurity struct {
 	// Vendor extensions
 	VendorExtensions map[string]any `json:"vendorExtensions"`
 }
+
+// CodegenSecurityRequirement represents one alternative of an operation's security.
+// The schemes of a requirement must all be satisfied together, while the requirements
+// of an operation are alternatives. An empty requirement makes authentication optional.
+type CodegenSecurityRequirement struct {
+	AuthMethods    []*CodegenSecurity `json:"authMethods"` // Schemes with the scopes required by this alternative
+	HasAuthMethods bool               `json:"hasAuthMethods"`
+	IsAnonymous    bool               `json:"isAnonymous"` // Empty requirement ({})
+}
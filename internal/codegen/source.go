@@ -0,0 +1,34 @@
+package codegen
+
+import "fmt"
+
+// Source locates the part of the OpenAPI document a codegen struct was created from.
+type Source struct {
+	File    string `json:"file,omitempty"`    // Spec file or URL
+	Pointer string `json:"pointer,omitempty"` // JSON pointer within the file, e.g. #/components/schemas/Pet
+	Line    int    `json:"line,omitempty"`    // 1-based, 0 when unknown
+	Column  int    `json:"column,omitempty"`  // 1-based, 0 when unknown
+}
+
+// String formats the source like "spec.yaml:12:5 #/components/schemas/Pet",
+// or "line 12, column 5 #/components/schemas/Pet" without a file.
+func (s *Source) String() string {
+	if s == nil {
+		return "unknown location"
+	}
+	location := s.File
+	switch {
+	case s.Line > 0 && location == "":
+		location = fmt.Sprintf("line %d, column %d", s.Line, s.Column)
+	case s.Line > 0:
+		location = fmt.Sprintf("%s:%d:%d", location, s.Line, s.Column)
+	}
+	switch {
+	case location == "":
+		return s.Pointer
+	case s.Pointer == "":
+		return location
+	default:
+		return location + " " + s.Pointer
+	}
+}
@@ -4,9 +4,10 @@ package config
 // GeneratorConfig holds configuration for code generation.
 type GeneratorConfig struct {
 	// Input/Output
-	InputSpec   string `json:"inputSpec"`
-	OutputDir   string `json:"outputDir"`
-	TemplateDir string `json:"templateDir,omitempty"`
+	InputSpec   string   `json:"inputSpec"`
+	InputSpecs  []string `json:"inputSpecs,omitempty"` // All input specs when several are merged
+	OutputDir   string   `json:"outputDir"`
+	TemplateDir string   `json:"templateDir,omitempty"`
 
 	// Generator identification
 	GeneratorName string `json:"generatorName"`
@@ -60,7 +61,7 @@ type TypeScriptFetchConfig struct {
 	ModelPropertyNaming string `json:"modelPropertyNaming,omitempty"` // original, camelCase, PascalCase, snake_case
 
 	// Enum property naming
-	EnumPropertyNaming string `json:"enumPropertyNaming,omitempty"`
+	EnumPropertyNaming string `json:"enumPropertyNaming,omitempty"` // UPPERCASE, original, camelCase, PascalCase, snake_case
 
 	// Null-safe additional props
 	NullSafeAdditionalProps bool `json:"nullSafeAdditionalProps,omitempty"`
@@ -119,4 +120,5 @@ const (
 	PropertyNamingCamelCase  ModelPropertyNamingType = "camelCase"
 	PropertyNamingPascalCase ModelPropertyNamingType = "PascalCase"
 	PropertyNamingSnakeCase  ModelPropertyNamingType = "snake_case"
+	PropertyNamingUpperCase  ModelPropertyNamingType = "UPPERCASE" // Enum constants only
 )
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseEnumNameMappings parses "value=NAME" pairs separated by commas, e.g.
+// "in-progress=ACTIVE,-1=UNKNOWN". A pair is split on its first "=", so names
+// may contain "=". Commas only separate pairs when the text on both sides
+// contains "=": "a,b=X" maps "a,b" and "a=X,Y" maps a to "X,Y".
+func ParseEnumNameMappings(values []string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		var last, pending string
+		paired := false
+		for _, segment := range strings.Split(value, ",") {
+			enumValue, name, found := strings.Cut(segment, "=")
+			switch {
+			case found:
+				last = strings.TrimSpace(pending + enumValue)
+				result[last] = strings.TrimSpace(name)
+				pending, paired = "", true
+			case paired:
+				result[last] += "," + strings.TrimSpace(segment)
+			default:
+				pending += segment + ","
+			}
+		}
+		if !paired {
+			return nil, fmt.Errorf("invalid enum name mapping %q: expected value=NAME", value)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_ParseEnumNameMappings(t *testing.T) {
+	tests := []struct {
+		values  []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			values: []string{"in-progress=ACTIVE, -1=UNKNOWN", "done=DONE"},
+			want:   map[string]string{"in-progress": "ACTIVE", "-1": "UNKNOWN", "done": "DONE"},
+		},
+		{values: []string{"a=b=c"}, want: map[string]string{"a": "b=c"}},
+		{values: []string{"a=x,y,b=z"}, want: map[string]string{"a": "x,y", "b": "z"}},
+		{values: []string{"a,b=COMMA"}, want: map[string]string{"a,b": "COMMA"}},
+		{values: []string{"=EMPTY"}, want: map[string]string{"": "EMPTY"}},
+		{values: []string{""}, want: map[string]string{}},
+		{values: []string{"ACTIVE"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseEnumNameMappings(tt.values)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseEnumNameMappings(%q) error = %v", tt.values, err)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseEnumNameMappings(%q) = %v, want %v", tt.values, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Global property names, as used by the Java openapi-generator.
+const (
+	GlobalPropertyModels               = "models"
+	GlobalPropertyApis                 = "apis"
+	GlobalPropertySupportingFiles      = "supportingFiles"
+	GlobalPropertyDebugModels          = "debugModels"
+	GlobalPropertyDebugOperations      = "debugOperations"
+	GlobalPropertyDebugSupportingFiles = "debugSupportingFiles"
+)
+
+// FileSelection selects the files of one kind to generate.
+type FileSelection struct {
+	All   bool            // Every file is generated
+	Names map[string]bool // Otherwise, only the named files are generated
+}
+
+// Includes reports whether a file known under any of the given names is selected.
+func (s FileSelection) Includes(names ...string) bool {
+	if s.All {
+		return true
+	}
+	for _, name := range names {
+		if s.Names[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRestricted reports whether only some files are selected.
+func (s FileSelection) IsRestricted() bool {
+	return !s.All
+}
+
+// ParseGlobalProperties parses "name=value" pairs separated by commas,
+// e.g. "models=Pet:Order,apis=false". A name without value is set to "".
+func ParseGlobalProperties(values []string) map[string]any {
+	result := make(map[string]any)
+	for _, value := range values {
+		for _, pair := range strings.Split(value, ",") {
+			name, v, _ := strings.Cut(pair, "=")
+			if name = strings.TrimSpace(name); name != "" {
+				result[name] = strings.TrimSpace(v)
+			}
+		}
+	}
+	return result
+}
+
+// FileSelections returns the models, APIs and supporting files to generate.
+// Like in the Java generator, setting any of the models, apis or supportingFiles
+// global properties restricts generation to the kinds that are set. A value
+// that is empty or true selects all files of a kind, false none, and a
+// colon-separated list (e.g. "Pet:Order") only the named files.
+func (c *GeneratorConfig) FileSelections() (models, apis, supportingFiles FileSelection) {
+	kinds := []string{GlobalPropertyModels, GlobalPropertyApis, GlobalPropertySupportingFiles}
+	selections := make([]FileSelection, len(kinds))
+
+	restricted := false
+	for _, kind := range kinds {
+		if value, ok := c.GlobalProperties[kind]; ok && !isFalse(value) {
+			restricted = true
+		}
+	}
+
+	for i, kind := range kinds {
+		value, ok := c.GlobalProperties[kind]
+		switch {
+		case !ok:
+			selections[i].All = !restricted
+		case isFalse(value):
+		case isTrue(value):
+			selections[i].All = true
+		default:
+			selections[i].Names = make(map[string]bool)
+			for _, name := range strings.Split(fmt.Sprint(value), ":") {
+				if name = strings.TrimSpace(name); name != "" {
+					selections[i].Names[name] = true
+				}
+			}
+		}
+	}
+	return selections[0], selections[1], selections[2]
+}
+
+// GlobalSwitch reports whether a global property switch such as debugModels is on.
+// A switch is on when set without value or to true.
+func (c *GeneratorConfig) GlobalSwitch(name string) bool {
+	value, ok := c.GlobalProperties[name]
+	return ok && isTrue(value)
+}
+
+func isTrue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return v
+	default:
+		s := strings.TrimSpace(fmt.Sprint(v))
+		return s == "" || strings.EqualFold(s, "true")
+	}
+}
+
+func isFalse(value any) bool {
+	switch v := value.(type) {
+	case bool:
+		return !v
+	case string:
+		return strings.EqualFold(strings.TrimSpace(v), "false")
+	default:
+		return false
+	}
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_ParseGlobalProperties(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   map[string]any
+	}{
+		{
+			name:   "pairs",
+			values: []string{"models=Pet:Tag,apis=false", " supportingFiles = runtime.ts "},
+			want:   map[string]any{"models": "Pet:Tag", "apis": "false", "supportingFiles": "runtime.ts"},
+		},
+		{
+			name:   "switches without value",
+			values: []string{"debugModels", "apis="},
+			want:   map[string]any{"debugModels": "", "apis": ""},
+		},
+		{
+			name:   "later values win",
+			values: []string{"models=Pet", "models=Tag"},
+			want:   map[string]any{"models": "Tag"},
+		},
+		{
+			name:   "unknown keys are kept",
+			values: []string{"skipFormModel=true"},
+			want:   map[string]any{"skipFormModel": "true"},
+		},
+		{
+			name:   "malformed pairs are skipped",
+			values: []string{",,=orphan, =x,"},
+			want:   map[string]any{},
+		},
+		{
+			name:   "value with an equals sign",
+			values: []string{"models=a=b"},
+			want:   map[string]any{"models": "a=b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseGlobalProperties(tt.values); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseGlobalProperties(%q) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_FileSelections(t *testing.T) {
+	all := FileSelection{All: true}
+	none := FileSelection{}
+	tests := []struct {
+		name                          string
+		properties                    map[string]any
+		models, apis, supportingFiles FileSelection
+	}{
+		{name: "nothing set", models: all, apis: all, supportingFiles: all},
+		{
+			name:       "named models only",
+			properties: map[string]any{"models": "Pet:Tag"},
+			models:     FileSelection{Names: map[string]bool{"Pet": true, "Tag": true}},
+			apis:       none, supportingFiles: none,
+		},
+		{
+			name:       "empty value selects all of a kind",
+			properties: map[string]any{"apis": ""},
+			models:     none, apis: all, supportingFiles: none,
+		},
+		{
+			name:       "true and nil select all of a kind",
+			properties: map[string]any{"apis": true, "supportingFiles": nil},
+			models:     none, apis: all, supportingFiles: all,
+		},
+		{
+			name:       "false alone restricts nothing else",
+			properties: map[string]any{"models": "false"},
+			models:     none, apis: all, supportingFiles: all,
+		},
+		{
+			name:       "false with another kind",
+			properties: map[string]any{"models": false, "apis": "TRUE"},
+			models:     none, apis: all, supportingFiles: none,
+		},
+		{
+			name:       "unknown keys restrict nothing",
+			properties: map[string]any{"debugModels": "", "skipFormModel": "true"},
+			models:     all, apis: all, supportingFiles: all,
+		},
+		{
+			name:       "list with blanks",
+			properties: map[string]any{"supportingFiles": "runtime.ts: :index.ts"},
+			models:     none, apis: none,
+			supportingFiles: FileSelection{Names: map[string]bool{"runtime.ts": true, "index.ts": true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &GeneratorConfig{GlobalProperties: tt.properties}
+			models, apis, supportingFiles := c.FileSelections()
+			for _, check := range []struct {
+				kind      string
+				got, want FileSelection
+			}{
+				{"models", models, tt.models},
+				{"apis", apis, tt.apis},
+				{"supportingFiles", supportingFiles, tt.supportingFiles},
+			} {
+				if !reflect.DeepEqual(check.got, check.want) {
+					t.Errorf("%s = %+v, want %+v", check.kind, check.got, check.want)
+				}
+			}
+		})
+	}
+}
+
+func Test_FileSelection_Includes(t *testing.T) {
+	selection := FileSelection{Names: map[string]bool{"Pet": true}}
+	if !selection.Includes("pet.ts", "Pet") || selection.Includes("Tag") || !selection.IsRestricted() {
+		t.Errorf("named selection = %+v", selection)
+	}
+	all := FileSelection{All: true}
+	if !all.Includes("anything") || all.IsRestricted() {
+		t.Errorf("selection of all files = %+v", all)
+	}
+	if (FileSelection{}).Includes("Pet") {
+		t.Error("empty selection includes Pet")
+	}
+}
+
+func Test_GlobalSwitch(t *testing.T) {
+	c := &GeneratorConfig{GlobalProperties: map[string]any{
+		"debugModels":          "",
+		"debugOperations":      "false",
+		"debugSupportingFiles": true,
+		"verbose":              "yes",
+		"nil":                  nil,
+	}}
+	tests := map[string]bool{
+		"debugModels":          true,
+		"debugOperations":      false,
+		"debugSupportingFiles": true,
+		"verbose":              false,
+		"nil":                  true,
+		"missing":              false,
+	}
+	for name, want := range tests {
+		if got := c.GlobalSwitch(name); got != want {
+			t.Errorf("GlobalSwitch(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
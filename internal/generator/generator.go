@@ -58,6 +58,9 @@ type CodegenConfig interface {
 	// GetSchemaType gets the language-specific type for a schema
 	GetSchemaType(schemaType string, format string) string
 
+	// GetPropertyTypeDeclaration gets the language-specific type of a property, including containers
+	GetPropertyTypeDeclaration(prop *codegen.CodegenProperty) string
+
 	// IsReservedWord checks if a word is reserved
 	IsReservedWord(word string) bool
 
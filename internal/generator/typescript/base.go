@@ -2,6 +2,8 @@
 package typescript
 
 import (
+	"fmt"
+	"path"
 	"regexp"
 	"strings"
 	"unicode"
@@ -80,7 +82,7 @@ var Primitives = map[string]bool{
 	"Double": true, "Integer": true, "Long": true, "Float": true,
 	"Object": true, "Array": true, "ReadonlyArray": true, "Date": true,
 	"number": true, "any": true, "File": true, "Error": true,
-	"Map": true, "Set": true,
+	"Map": true, "Set": true, "null": true, "void": true,
 }
 
 // BaseGenerator is the base generator for TypeScript languages.
@@ -111,8 +113,13 @@ type BaseGenerator struct {
 	// Naming configuration
 	ModelNamePrefix string
 	ModelNameSuffix string
+	ApiNamePrefix   string
 	ApiNameSuffix   string
 
+	// Names exposed to the templates
+	PackageName    string
+	InvokerPackage string
+
 	// Template files
 	ApiTemplateFiles   map[string]string
 	ModelTemplateFiles map[string]string
@@ -123,6 +130,7 @@ type BaseGenerator struct {
 
 	// Behavior flags
 	ModelPropertyNaming     config.ModelPropertyNamingType
+	EnumPropertyNaming      config.ModelPropertyNamingType
 	NullSafeAdditionalProps bool
 	AllowUnicodeIdentifiers bool
 }
@@ -145,6 +153,7 @@ func NewBaseGenerator() *BaseGenerator {
 		AdditionalProperties:       make(map[string]any),
 		ApiNameSuffix:              "Api",
 		ModelPropertyNaming:        config.PropertyNamingCamelCase,
+		EnumPropertyNaming:         config.PropertyNamingUpperCase,
 	}
 	return g
 }
@@ -218,6 +227,54 @@ func (g *BaseGenerator) GetTypeDeclaration(schemaType, format string) string {
 	return g.GetSchemaType(schemaType, format)
 }
 
+// GetPropertyTypeDeclaration returns the TypeScript type of a property, nesting
+// container types to any depth (e.g. Array<Set<{ [key: string]: Pet; }>>)
+func (g *BaseGenerator) GetPropertyTypeDeclaration(prop *codegen.CodegenProperty) string {
+	if !prop.IsArray && !prop.IsMap {
+		return prop.DataType
+	}
+
+	inner := "any"
+	if prop.Items != nil {
+		inner = g.GetPropertyTypeDeclaration(prop.Items)
+	}
+	if prop.IsMap {
+		return "{ [key: string]: " + inner + "; }"
+	}
+	if prop.UniqueItems {
+		return "Set<" + inner + ">"
+	}
+	return "Array<" + inner + ">"
+}
+
+// ExcludeType excludes the type of a not schema from a union type, e.g.
+// Exclude<string | number, string>. Enums are excluded as literals. Only pass
+// not schemas the parser found excludable (CodegenComposedSchemas.ExcludesNot);
+// the union is returned as is for nil.
+func (g *BaseGenerator) ExcludeType(union string, not *codegen.CodegenProperty) string {
+	if not == nil {
+		return union
+	}
+
+	excluded := not.DataType
+	if not.IsEnum {
+		values, _ := not.AllowableValues["values"].([]any)
+		literals := make([]string, 0, len(values))
+		for _, v := range values {
+			switch value := v.(type) {
+			case nil:
+				literals = append(literals, "null")
+			case string:
+				literals = append(literals, "'"+codegen.StringLiteralEscaper.Replace(value)+"'")
+			default:
+				literals = append(literals, fmt.Sprintf("%v", value))
+			}
+		}
+		excluded = strings.Join(literals, " | ")
+	}
+	return "Exclude<" + union + ", " + excluded + ">"
+}
+
 // ToModelName converts a schema name to a TypeScript model name
 func (g *BaseGenerator) ToModelName(name string) string {
 	// Check model name mapping
@@ -225,13 +282,13 @@ func (g *BaseGenerator) ToModelName(name string) string {
 		return mapped
 	}
 
-	// Apply prefix and suffix
+	// Apply prefix and suffix as separate words, so "Api" + "pet" becomes "ApiPet"
 	result := name
 	if g.ModelNamePrefix != "" {
-		result = g.ModelNamePrefix + result
+		result = g.ModelNamePrefix + "_" + result
 	}
 	if g.ModelNameSuffix != "" {
-		result = result + g.ModelNameSuffix
+		result = result + "_" + g.ModelNameSuffix
 	}
 
 	return g.toTypescriptTypeName(result, "Model")
@@ -239,7 +296,7 @@ func (g *BaseGenerator) ToModelName(name string) string {
 
 // ToApiName converts a tag to an API class name
 func (g *BaseGenerator) ToApiName(name string) string {
-	return Camelize(SanitizeName(name), false) + g.ApiNameSuffix
+	return Camelize(SanitizeName(g.ApiNamePrefix), false) + Camelize(SanitizeName(name), false) + g.ApiNameSuffix
 }
 
 // ToVarName converts a property name to a variable name
@@ -266,6 +323,31 @@ func (g *BaseGenerator) ToVarName(name string) string {
 	}
 }
 
+// ToEnumVarName returns the constant name of an enum value. EnumNameMapping
+// maps values to names; other names follow EnumPropertyNaming, e.g.
+// IN_PROGRESS, inProgress or InProgress for "in-progress".
+func (g *BaseGenerator) ToEnumVarName(value any) string {
+	if mapped, ok := g.EnumNameMapping[codegen.EnumLiteral(value)]; ok {
+		return mapped
+	}
+
+	words := codegen.EnumWords(value)
+	var name string
+	switch g.EnumPropertyNaming {
+	case config.PropertyNamingOriginal:
+		name = SanitizeName(words)
+	case config.PropertyNamingCamelCase:
+		name = Camelize(words, true)
+	case config.PropertyNamingPascalCase:
+		name = Camelize(words, false)
+	case config.PropertyNamingSnakeCase:
+		name = Underscore(SanitizeName(words))
+	default:
+		return codegen.EnumVarName(value)
+	}
+	return codegen.EnumIdentifier(name)
+}
+
 // ToParamName converts a parameter name
 func (g *BaseGenerator) ToParamName(name string) string {
 	return Camelize(SanitizeName(name), true)
@@ -399,8 +481,77 @@ func (g *BaseGenerator) SetConfig(cfg *config.GeneratorConfig) {
 	g.Config = cfg
 }
 
+// applyNamingConfig applies the package and naming options of the generator config.
+// Options left empty keep their defaults.
+func (g *BaseGenerator) applyNamingConfig(cfg *config.GeneratorConfig) {
+	if cfg == nil {
+		return
+	}
+	if cfg.ApiPackage != "" {
+		g.ApiPackage = cfg.ApiPackage
+	}
+	if cfg.ModelPackage != "" {
+		g.ModelPackage = cfg.ModelPackage
+	}
+	if cfg.ModelNamePrefix != "" {
+		g.ModelNamePrefix = cfg.ModelNamePrefix
+	}
+	if cfg.ModelNameSuffix != "" {
+		g.ModelNameSuffix = cfg.ModelNameSuffix
+	}
+	if cfg.ApiNamePrefix != "" {
+		g.ApiNamePrefix = cfg.ApiNamePrefix
+	}
+	if cfg.ApiNameSuffix != "" {
+		g.ApiNameSuffix = cfg.ApiNameSuffix
+	}
+	g.PackageName = cfg.PackageName
+	g.InvokerPackage = cfg.InvokerPackage
+}
+
+// ApiFolder returns the folder of the API files, relative to the output directory
+func (g *BaseGenerator) ApiFolder() string {
+	return packageFolder(g.ApiPackage)
+}
+
+// ModelFolder returns the folder of the model files, relative to the output directory
+func (g *BaseGenerator) ModelFolder() string {
+	return packageFolder(g.ModelPackage)
+}
+
+// RelativeToRoot returns the import path prefix leading from a folder back to
+// the output directory, e.g. "../../" for "src/models".
+func RelativeToRoot(folder string) string {
+	if folder == "" {
+		return "./"
+	}
+	return strings.Repeat("../", strings.Count(folder, "/")+1)
+}
+
 // Helper functions
 
+// packageFolder converts a package name to a clean slash-separated folder,
+// so both "api.models" and "api/models" become "api/models". "." and ".."
+// segments are resolved; a folder outside the output directory starts with "..".
+func packageFolder(pkg string) string {
+	segments := strings.Split(strings.ReplaceAll(pkg, "\\", "/"), "/")
+	for i, segment := range segments {
+		if segment != "." && segment != ".." {
+			segments[i] = strings.ReplaceAll(segment, ".", "/")
+		}
+	}
+	folder := path.Clean(strings.TrimLeft(strings.Join(segments, "/"), "/"))
+	if folder == "." {
+		return ""
+	}
+	return folder
+}
+
+// isOutsideOutput reports whether a folder of packageFolder escapes the output directory.
+func isOutsideOutput(folder string) bool {
+	return folder == ".." || strings.HasPrefix(folder, "../")
+}
+
 func copyMap(m map[string]string) map[string]string {
 	result := make(map[string]string)
 	for k, v := range m {
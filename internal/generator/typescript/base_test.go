@@ -0,0 +1,174 @@
+package typescript
+
+import (
+	"testing"
+
+	"github.com/xseman/openapi-generator/internal/codegen"
+	"github.com/xseman/openapi-generator/internal/config"
+)
+
+func Test_GetPropertyTypeDeclaration_nested(t *testing.T) {
+	pet := &codegen.CodegenProperty{DataType: "Pet"}
+	tests := []struct {
+		name string
+		prop *codegen.CodegenProperty
+		want string
+	}{
+		{"plain", pet, "Pet"},
+		{"array", &codegen.CodegenProperty{IsArray: true, Items: pet}, "Array<Pet>"},
+		{"set", &codegen.CodegenProperty{IsArray: true, UniqueItems: true, Items: pet}, "Set<Pet>"},
+		{"map", &codegen.CodegenProperty{IsMap: true, Items: pet}, "{ [key: string]: Pet; }"},
+		{"untyped array", &codegen.CodegenProperty{IsArray: true}, "Array<any>"},
+		{
+			"array of sets of maps",
+			&codegen.CodegenProperty{IsArray: true, Items: &codegen.CodegenProperty{
+				IsArray: true, UniqueItems: true, Items: &codegen.CodegenProperty{IsMap: true, Items: pet},
+			}},
+			"Array<Set<{ [key: string]: Pet; }>>",
+		},
+	}
+	g := NewBaseGenerator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.GetPropertyTypeDeclaration(tt.prop); got != tt.want {
+				t.Errorf("GetPropertyTypeDeclaration() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ToModelName_prefixAndSuffix(t *testing.T) {
+	tests := []struct {
+		prefix, suffix, name string
+		want                 string
+	}{
+		{"", "", "pet", "Pet"},
+		{"Api", "", "Pet", "ApiPet"},
+		// Prefix and suffix are separate words, like in the Java generator
+		{"Api", "", "pet", "ApiPet"},
+		{"", "dto", "Pet", "PetDto"},
+		{"my", "Model", "order_item", "MyOrderItemModel"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix+"+"+tt.name+"+"+tt.suffix, func(t *testing.T) {
+			g := NewBaseGenerator()
+			g.ModelNamePrefix, g.ModelNameSuffix = tt.prefix, tt.suffix
+			if got := g.ToModelName(tt.name); got != tt.want {
+				t.Errorf("ToModelName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	g := NewBaseGenerator()
+	g.ModelNamePrefix = "Api"
+	g.ModelNameMapping["pet"] = "Animal"
+	if got := g.ToModelName("pet"); got != "Animal" {
+		t.Errorf("mapped ToModelName = %q, want the mapping without prefix", got)
+	}
+}
+
+func Test_packageFolder(t *testing.T) {
+	tests := []struct {
+		pkg     string
+		want    string
+		outside bool
+	}{
+		{"", "", false},
+		{".", "", false},
+		{"models", "models", false},
+		{"api.models", "api/models", false},
+		{"src/models", "src/models", false},
+		{"src\\models", "src/models", false},
+		{"./src/models/", "src/models", false},
+		{"src/../models", "models", false},
+		// Absolute folders are taken relative to the output directory
+		{"/models", "models", false},
+		{"//src//models", "src/models", false},
+		{"..", "..", true},
+		{"../models", "../models", true},
+		{"src/../../models", "../models", true},
+		{"..\\models", "../models", true},
+		{"..models", "models", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pkg, func(t *testing.T) {
+			got := packageFolder(tt.pkg)
+			if got != tt.want {
+				t.Errorf("packageFolder(%q) = %q, want %q", tt.pkg, got, tt.want)
+			}
+			if outside := isOutsideOutput(got); outside != tt.outside {
+				t.Errorf("isOutsideOutput(%q) = %v, want %v", got, outside, tt.outside)
+			}
+		})
+	}
+}
+
+func Test_RelativeToRoot(t *testing.T) {
+	tests := map[string]string{
+		"":               "./",
+		"models":         "../",
+		"src/models":     "../../",
+		"src/gen/models": "../../../",
+	}
+	for folder, want := range tests {
+		if got := RelativeToRoot(folder); got != want {
+			t.Errorf("RelativeToRoot(%q) = %q, want %q", folder, got, want)
+		}
+	}
+}
+
+func Test_ApiFolder_ModelFolder(t *testing.T) {
+	g := NewBaseGenerator()
+	g.ApiPackage, g.ModelPackage = "src.apis", "src/models/"
+	if got := g.ApiFolder(); got != "src/apis" {
+		t.Errorf("ApiFolder() = %q", got)
+	}
+	if got := g.ModelFolder(); got != "src/models" {
+		t.Errorf("ModelFolder() = %q", got)
+	}
+}
+
+func Test_ToEnumVarName_naming(t *testing.T) {
+	tests := []struct {
+		naming config.ModelPropertyNamingType
+		value  any
+		want   string
+	}{
+		{config.PropertyNamingUpperCase, "in-progress", "IN_PROGRESS"},
+		{config.PropertyNamingUpperCase, float64(-1), "MINUS_1"},
+		{config.PropertyNamingCamelCase, "in-progress", "inProgress"},
+		{config.PropertyNamingPascalCase, "in-progress", "InProgress"},
+		{config.PropertyNamingPascalCase, "", "Empty"},
+		{config.PropertyNamingSnakeCase, "inProgress", "in_progress"},
+		{config.PropertyNamingOriginal, "in_progress", "in_progress"},
+		{config.PropertyNamingCamelCase, "2fa", "_2fa"},
+	}
+	for _, tt := range tests {
+		g := NewBaseGenerator()
+		g.EnumPropertyNaming = tt.naming
+		if got := g.ToEnumVarName(tt.value); got != tt.want {
+			t.Errorf("%s: ToEnumVarName(%#v) = %q, want %q", tt.naming, tt.value, got, tt.want)
+		}
+	}
+}
+
+func Test_ToEnumVarName_mapping(t *testing.T) {
+	g := NewBaseGenerator()
+	g.EnumNameMapping = map[string]string{"in-progress": "ACTIVE", "-1": "UNKNOWN", "null": "NONE", "": "BLANK"}
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{"in-progress", "ACTIVE"},
+		{float64(-1), "UNKNOWN"},
+		{"-1", "UNKNOWN"},
+		{nil, "NONE"},
+		{"", "BLANK"},
+		{"done", "DONE"},
+	}
+	for _, tt := range tests {
+		if got := g.ToEnumVarName(tt.value); got != tt.want {
+			t.Errorf("ToEnumVarName(%#v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
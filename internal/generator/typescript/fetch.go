@@ -1,6 +1,7 @@
 package typescript
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/xseman/openapi-generator/internal/codegen"
@@ -38,6 +39,9 @@ func NewFetchGenerator() *FetchGenerator {
 	// Set up template files
 	g.ApiTemplateFiles["apis.mustache"] = ".ts"
 
+	// Binary content is a built-in Blob, never an imported model
+	g.LanguageSpecificPrimitives["Blob"] = true
+
 	// Add extra reserved words
 	g.addExtraReservedWords()
 
@@ -78,12 +82,32 @@ func (g *FetchGenerator) ProcessOpts() error {
 		if g.TSConfig.ModelPropertyNaming != "" {
 			g.ModelPropertyNaming = config.ModelPropertyNamingType(g.TSConfig.ModelPropertyNaming)
 		}
+		if g.TSConfig.EnumPropertyNaming != "" {
+			naming := config.ModelPropertyNamingType(g.TSConfig.EnumPropertyNaming)
+			switch naming {
+			case config.PropertyNamingUpperCase, config.PropertyNamingOriginal, config.PropertyNamingCamelCase,
+				config.PropertyNamingPascalCase, config.PropertyNamingSnakeCase:
+				g.EnumPropertyNaming = naming
+			default:
+				return fmt.Errorf("invalid enumPropertyNaming %q (expected UPPERCASE, original, camelCase, PascalCase or snake_case)", naming)
+			}
+		}
 	}
 
-	// Set up source directory
+	// Set up source directory and packages, unless configured otherwise
 	g.SourceDir = ""
 	g.ApiPackage = "apis"
 	g.ModelPackage = "models"
+	g.applyNamingConfig(g.Config)
+
+	for _, pkg := range []string{g.ApiPackage, g.ModelPackage} {
+		if folder := packageFolder(pkg); folder == "" || isOutsideOutput(folder) {
+			return fmt.Errorf("apiPackage and modelPackage must name a folder below the output directory, not %q", pkg)
+		}
+	}
+	if g.ApiFolder() == g.ModelFolder() {
+		return fmt.Errorf("apiPackage and modelPackage must differ, both are %q", g.ApiFolder())
+	}
 
 	// Set up additional properties for templates
 	g.AdditionalProperties["withPackageJson"] = g.WithPackageJson
@@ -96,6 +120,7 @@ func (g *FetchGenerator) ProcessOpts() error {
 	g.AdditionalProperties["validationAttributes"] = g.ValidationAttributes
 	g.AdditionalProperties["isOriginalModelPropertyNaming"] = g.ModelPropertyNaming == config.PropertyNamingOriginal
 	g.AdditionalProperties["modelPropertyNaming"] = string(g.ModelPropertyNaming)
+	g.AdditionalProperties["enumPropertyNaming"] = string(g.EnumPropertyNaming)
 
 	// Add supporting files
 	g.SupportingFiles = append(g.SupportingFiles,
@@ -174,9 +199,23 @@ func (g *FetchGenerator) processCodeGenModel(cm *codegen.CodegenModel) {
 		g.processCodegenProperty(v, cm.Classname)
 	}
 
+	// A mapping to the model itself is handled apart, as it must not dispatch again
+	if cm.Discriminator != nil {
+		mappedModels := cm.Discriminator.MappedModels[:0]
+		for _, mm := range cm.Discriminator.MappedModels {
+			if mm.ModelName == cm.Classname {
+				cm.HasSelfReferencingDiscriminatorMapping = true
+				cm.SelfReferencingDiscriminatorMapping = mm
+				continue
+			}
+			mappedModels = append(mappedModels, mm)
+		}
+		cm.Discriminator.MappedModels = mappedModels
+	}
+
 	// Process parent vars for inheritance
 	if cm.Parent != "" {
-		for _, v := range cm.AllVars {
+		for _, v := range cm.ParentVars {
 			if v.IsEnum {
 				v.DatatypeWithEnum = strings.Replace(
 					v.DatatypeWithEnum,
@@ -192,7 +231,7 @@ func (g *FetchGenerator) processCodeGenModel(cm *codegen.CodegenModel) {
 // processCodegenProperty processes a property for TypeScript-Fetch specific transformations
 func (g *FetchGenerator) processCodegenProperty(prop *codegen.CodegenProperty, parentClassName string) {
 	// Name enum with model name, e.g., StatusEnum => PetStatusEnum
-	if prop.IsEnum {
+	if prop.IsEnum && !prop.IsEnumRef {
 		prop.DatatypeWithEnum = strings.Replace(
 			prop.DatatypeWithEnum,
 			prop.EnumName,
@@ -234,10 +273,11 @@ func (g *FetchGenerator) escapeOperationId(op *codegen.CodegenOperation) {
 	}
 }
 
-// updateOperationParameterForEnum updates parameter enum names
+// updateOperationParameterForEnum updates parameter enum names. References to
+// enum models keep the model name.
 func (g *FetchGenerator) updateOperationParameterForEnum(op *codegen.CodegenOperation) {
 	for _, param := range op.AllParams {
-		if param.IsEnum {
+		if param.IsEnum && !param.IsEnumRef {
 			param.DatatypeWithEnum = strings.Replace(
 				param.DatatypeWithEnum,
 				param.EnumName,
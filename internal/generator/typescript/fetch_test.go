@@ -0,0 +1,76 @@
+package typescript
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/xseman/openapi-generator/internal/codegen"
+	"github.com/xseman/openapi-generator/internal/config"
+)
+
+func Test_ProcessOpts_packages(t *testing.T) {
+	tests := []struct {
+		name                string
+		apiPackage, model   string
+		wantApi, wantModels string
+		wantErr             string
+	}{
+		{name: "defaults", wantApi: "apis", wantModels: "models"},
+		{name: "nested", apiPackage: "src.apis", model: "src/models", wantApi: "src/apis", wantModels: "src/models"},
+		{name: "absolute", apiPackage: "/gen/apis", model: "/gen/models", wantApi: "gen/apis", wantModels: "gen/models"},
+		{name: "traversal", apiPackage: "../apis", wantErr: "below the output directory"},
+		{name: "hidden traversal", model: "src/../../models", wantErr: "below the output directory"},
+		{name: "output directory", model: "./", wantErr: "below the output directory"},
+		{name: "same folder", apiPackage: "src.gen", model: "src/gen", wantErr: "must differ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewFetchGenerator()
+			g.SetConfig(&config.GeneratorConfig{ApiPackage: tt.apiPackage, ModelPackage: tt.model})
+			err := g.ProcessOpts()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ProcessOpts() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProcessOpts: %v", err)
+			}
+			if g.ApiFolder() != tt.wantApi || g.ModelFolder() != tt.wantModels {
+				t.Errorf("folders = %q, %q, want %q, %q", g.ApiFolder(), g.ModelFolder(), tt.wantApi, tt.wantModels)
+			}
+		})
+	}
+}
+
+func Test_processCodegenProperty_enumNames(t *testing.T) {
+	inline := &codegen.CodegenProperty{IsEnum: true, EnumName: "StatusEnum", DatatypeWithEnum: "StatusEnum", DefaultValue: "StatusEnum.Available"}
+	ref := &codegen.CodegenProperty{IsEnum: true, IsEnumRef: true, EnumName: "Status", DatatypeWithEnum: "Status"}
+
+	g := NewFetchGenerator()
+	g.processCodegenProperty(inline, "Pet")
+	g.processCodegenProperty(ref, "Pet")
+
+	if inline.DatatypeWithEnum != "PetStatusEnum" || inline.DefaultValue != "PetStatusEnum.Available" {
+		t.Errorf("inline enum = %s, default %s; want it named after the model", inline.DatatypeWithEnum, inline.DefaultValue)
+	}
+	if ref.DatatypeWithEnum != "Status" {
+		t.Errorf("enum reference = %s, want the shared Status", ref.DatatypeWithEnum)
+	}
+}
+
+func Test_updateOperationParameterForEnum_enumNames(t *testing.T) {
+	inline := &codegen.CodegenParameter{IsEnum: true, EnumName: "ModeEnum", DatatypeWithEnum: "ModeEnum"}
+	ref := &codegen.CodegenParameter{IsEnum: true, IsEnumRef: true, EnumName: "Status", DatatypeWithEnum: "Status"}
+	op := &codegen.CodegenOperation{OperationIdCamelCase: "ListPets", AllParams: []*codegen.CodegenParameter{inline, ref}}
+
+	NewFetchGenerator().updateOperationParameterForEnum(op)
+
+	if inline.DatatypeWithEnum != "ListPetsModeEnum" {
+		t.Errorf("inline enum = %s, want it named after the operation", inline.DatatypeWithEnum)
+	}
+	if ref.DatatypeWithEnum != "Status" {
+		t.Errorf("enum reference = %s, want the shared Status", ref.DatatypeWithEnum)
+	}
+}
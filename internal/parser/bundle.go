@@ -0,0 +1,277 @@
+package parser
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v3"
+)
+
+// BundleMode decides how the references of a multi-file spec are resolved.
+type BundleMode string
+
+// Bundle modes
+const (
+	BundleInline      BundleMode = "bundle"      // Move external references into components
+	BundleDereference BundleMode = "dereference" // Inline every reference, except recursive ones
+)
+
+// ParseBundleMode parses a bundle mode, defaulting to BundleInline.
+func ParseBundleMode(value string) (BundleMode, error) {
+	switch BundleMode(value) {
+	case "", BundleInline:
+		return BundleInline, nil
+	case BundleDereference:
+		return BundleDereference, nil
+	}
+	return "", fmt.Errorf("invalid bundle mode %q (expected bundle or dereference)", value)
+}
+
+// Bundle returns the loaded document as a single JSON or YAML file.
+// External references are moved into components under the name of the
+// referenced component or file, numbered when the name is already taken by
+// another component. When dereferencing, all references are inlined and only
+// the components still referenced, recursively or by discriminator mappings,
+// are kept.
+func (p *Parser) Bundle(mode BundleMode, format string) ([]byte, error) {
+	if p.Doc == nil {
+		return nil, fmt.Errorf("no document loaded")
+	}
+
+	doc, err := rawDocument(p.Doc)
+	if err != nil {
+		return nil, err
+	}
+	if mode == BundleDereference {
+		dereferenceDocument(doc)
+	}
+	return encodeDocument(doc, format)
+}
+
+// bundleRefNameResolver names internalized components after the component or
+// file they were loaded from, e.g. "common.yaml#/components/schemas/Error"
+// becomes Error, and "pet.yaml" becomes pet.
+func bundleRefNameResolver() openapi3.RefNameResolver {
+	names := make(map[string]string)
+	return func(doc *openapi3.T, ref openapi3.ComponentRef) string {
+		key := ref.CollectionName() + " " + ref.RefString()
+		if refPath := ref.RefPath(); refPath != nil {
+			key = ref.CollectionName() + " " + refPath.String()
+		}
+		if name, ok := names[key]; ok {
+			return name
+		}
+		if name, ok := openapi3.ReferencesComponentInRootDocument(doc, ref); ok {
+			names[key] = path.Base(name)
+			return names[key]
+		}
+
+		taken := func(name string) bool {
+			if hasComponent(doc.Components, ref.CollectionName(), name) {
+				return true
+			}
+			for other, assigned := range names {
+				if assigned == name && strings.HasPrefix(other, ref.CollectionName()+" ") {
+					return true
+				}
+			}
+			return false
+		}
+
+		base := componentBaseName(ref)
+		name := base
+		for i := 2; taken(name); i++ {
+			name = base + strconv.Itoa(i)
+		}
+		names[key] = name
+		return name
+	}
+}
+
+// componentBaseName returns the last segment of a reference, or the file name
+// without extension when the reference points to a whole file.
+func componentBaseName(ref openapi3.ComponentRef) string {
+	refPath := ref.RefPath()
+	if refPath == nil {
+		refPath = &url.URL{Path: ref.RefString()}
+	}
+
+	name := ""
+	if fragment := strings.Trim(refPath.Fragment, "/"); fragment != "" {
+		name = path.Base(fragment)
+		name = strings.ReplaceAll(strings.ReplaceAll(name, "~1", "/"), "~0", "~")
+	} else {
+		name = path.Base(refPath.Path)
+		for ext := path.Ext(name); ext != ""; ext = path.Ext(name) {
+			name = strings.TrimSuffix(name, ext)
+		}
+	}
+	name = openapi3.InvalidIdentifierCharRegExp.ReplaceAllString(name, "_")
+	if name == "" {
+		name = ref.CollectionName()
+	}
+	return name
+}
+
+// hasComponent reports whether a component of a collection exists.
+func hasComponent(components *openapi3.Components, collection, name string) bool {
+	if components == nil {
+		return false
+	}
+	var ok bool
+	switch collection {
+	case "schemas":
+		_, ok = components.Schemas[name]
+	case "parameters":
+		_, ok = components.Parameters[name]
+	case "headers":
+		_, ok = components.Headers[name]
+	case "requestBodies":
+		_, ok = components.RequestBodies[name]
+	case "responses":
+		_, ok = components.Responses[name]
+	case "securitySchemes":
+		_, ok = components.SecuritySchemes[name]
+	case "examples":
+		_, ok = components.Examples[name]
+	case "links":
+		_, ok = components.Links[name]
+	case "callbacks":
+		_, ok = components.Callbacks[name]
+	}
+	return ok
+}
+
+// dereferenceDocument inlines every local reference of a raw document. A
+// reference to a component being inlined is kept, so recursive schemas stay
+// finite. Components that are no longer referenced are dropped, except
+// security schemes, which are referenced by name.
+func dereferenceDocument(doc map[string]any) {
+	resolve := func(ref string) any {
+		var value any = doc
+		for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			object, ok := value.(map[string]any)
+			if !ok {
+				return nil
+			}
+			value = object[strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")]
+		}
+		return value
+	}
+
+	var inline func(value any, inlining map[string]bool) any
+	inline = func(value any, inlining map[string]bool) any {
+		switch v := value.(type) {
+		case map[string]any:
+			if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, "#/") && !inlining[ref] {
+				if target := resolve(ref); target != nil {
+					inlining[ref] = true
+					defer delete(inlining, ref)
+					return inline(target, inlining)
+				}
+			}
+			object := make(map[string]any, len(v))
+			for key, child := range v {
+				object[key] = inline(child, inlining)
+			}
+			return object
+		case []any:
+			array := make([]any, len(v))
+			for i, child := range v {
+				array[i] = inline(child, inlining)
+			}
+			return array
+		default:
+			return v
+		}
+	}
+
+	components, _ := doc["components"].(map[string]any)
+	for key, value := range doc {
+		if key != "components" {
+			doc[key] = inline(value, make(map[string]bool))
+		}
+	}
+	for _, kind := range componentKinds {
+		section, _ := components[kind].(map[string]any)
+		for name, value := range section {
+			section[name] = inline(value, map[string]bool{"#/components/" + kind + "/" + name: true})
+		}
+	}
+
+	// Keep the components still referenced, directly or through other components
+	used := make(map[string]bool)
+	var queue []string
+	var collect func(value any)
+	collect = func(value any) {
+		switch v := value.(type) {
+		case map[string]any:
+			for key, child := range v {
+				if ref, ok := child.(string); ok && (key == "$ref" || isDiscriminatorMapping(v)) && !used[ref] {
+					used[ref] = true
+					queue = append(queue, ref)
+				}
+				collect(child)
+			}
+		case []any:
+			for _, child := range v {
+				collect(child)
+			}
+		}
+	}
+	for key, value := range doc {
+		if key != "components" {
+			collect(value)
+		}
+	}
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		collect(resolve(ref))
+	}
+
+	for _, kind := range componentKinds {
+		if kind == "securitySchemes" {
+			continue
+		}
+		section, _ := components[kind].(map[string]any)
+		for name := range section {
+			if !used["#/components/"+kind+"/"+name] {
+				delete(section, name)
+			}
+		}
+		if section != nil && len(section) == 0 {
+			delete(components, kind)
+		}
+	}
+	if components != nil && len(components) == 0 {
+		delete(doc, "components")
+	}
+}
+
+// isDiscriminatorMapping reports whether an object looks like the mapping of a
+// discriminator, whose values are schema references.
+func isDiscriminatorMapping(object map[string]any) bool {
+	for _, value := range object {
+		ref, ok := value.(string)
+		if !ok || !strings.HasPrefix(ref, "#/components/schemas/") {
+			return false
+		}
+	}
+	return len(object) > 0
+}
+
+// encodeDocument encodes a raw document as "json" or "yaml", keeping the
+// top-level fields in their usual order.
+func encodeDocument(doc map[string]any, format string) ([]byte, error) {
+	var node yaml.Node
+	if err := node.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode document: %w", err)
+	}
+	orderDocumentNode(&node)
+	return encodeNode(&node, format)
+}
@@ -0,0 +1,229 @@
+package parser
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const bundleSpec = `openapi: 3.0.3
+info:
+  title: Bundle
+  version: "1"
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: pet.yaml
+        default:
+          description: error
+          content:
+            application/json:
+              schema:
+                $ref: common.yaml#/components/schemas/Error
+components:
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+  schemas:
+    Error:
+      type: object
+      properties:
+        code:
+          type: integer
+    Unused:
+      type: string
+`
+
+const bundlePet = `type: object
+properties:
+  name:
+    type: string
+  parent:
+    $ref: pet.yaml
+`
+
+const bundleCommon = `components:
+  schemas:
+    Error:
+      type: object
+      properties:
+        message:
+          type: string
+`
+
+// loadBundleSpec loads the multi-file bundle spec.
+func loadBundleSpec(t *testing.T) *Parser {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range map[string]string{"main.yaml": bundleSpec, "pet.yaml": bundlePet, "common.yaml": bundleCommon} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p := NewParser()
+	if err := p.LoadFromFile(filepath.Join(dir, "main.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// bundled bundles the loaded spec as JSON and decodes it.
+func bundled(t *testing.T, p *Parser, mode BundleMode) map[string]any {
+	t.Helper()
+	data, err := p.Bundle(mode, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("bundle is not JSON: %v\n%s", err, data)
+	}
+	return doc
+}
+
+// lookup returns the value under a sequence of keys of a raw document.
+func lookup(doc any, keys ...string) any {
+	for _, key := range keys {
+		object, ok := doc.(map[string]any)
+		if !ok {
+			return nil
+		}
+		doc = object[key]
+	}
+	return doc
+}
+
+// responseSchema returns the JSON schema of a GET /pets response.
+func responseSchema(doc map[string]any, status string) any {
+	return lookup(doc, "paths", "/pets", "get", "responses", status, "content", "application/json", "schema")
+}
+
+func Test_ParseBundleMode(t *testing.T) {
+	for value, want := range map[string]BundleMode{"": BundleInline, "bundle": BundleInline, "dereference": BundleDereference} {
+		if got, err := ParseBundleMode(value); err != nil || got != want {
+			t.Errorf("ParseBundleMode(%q) = %q, %v; want %q", value, got, err, want)
+		}
+	}
+	if _, err := ParseBundleMode("inline"); err == nil {
+		t.Error("ParseBundleMode(inline) succeeded, want an error")
+	}
+}
+
+func Test_Bundle_externalReferences(t *testing.T) {
+	doc := bundled(t, loadBundleSpec(t), BundleInline)
+
+	schemas, _ := lookup(doc, "components", "schemas").(map[string]any)
+	var names []string
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if want := []string{"Error", "Error2", "Unused", "pet"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("schemas = %v, want %v", names, want)
+	}
+
+	refs := []struct {
+		got  any
+		want string
+	}{
+		{lookup(responseSchema(doc, "200"), "$ref"), "#/components/schemas/pet"},
+		{lookup(responseSchema(doc, "default"), "$ref"), "#/components/schemas/Error2"},
+		{lookup(schemas, "pet", "properties", "parent", "$ref"), "#/components/schemas/pet"},
+	}
+	for _, ref := range refs {
+		if ref.got != ref.want {
+			t.Errorf("reference = %v, want %q", ref.got, ref.want)
+		}
+	}
+	if lookup(schemas, "Error", "properties", "code") == nil {
+		t.Error("the local Error schema was replaced")
+	}
+	if lookup(schemas, "Error2", "properties", "message") == nil {
+		t.Error("Error2 is not the external Error schema")
+	}
+}
+
+func Test_Bundle_dereference(t *testing.T) {
+	doc := bundled(t, loadBundleSpec(t), BundleDereference)
+
+	if lookup(responseSchema(doc, "default"), "properties", "message") == nil {
+		t.Errorf("error response schema = %v, want the inlined Error2", responseSchema(doc, "default"))
+	}
+	pet := responseSchema(doc, "200")
+	if lookup(pet, "properties", "name") == nil {
+		t.Errorf("pet response schema = %v, want the inlined pet", pet)
+	}
+	if ref := lookup(pet, "properties", "parent", "$ref"); ref != "#/components/schemas/pet" {
+		t.Errorf("recursive reference = %v, want it kept", ref)
+	}
+
+	schemas, _ := lookup(doc, "components", "schemas").(map[string]any)
+	var names []string
+	for name := range schemas {
+		names = append(names, name)
+	}
+	if want := []string{"pet"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("schemas = %v, want only the recursive %v", names, want)
+	}
+	if lookup(doc, "components", "securitySchemes", "apiKey") == nil {
+		t.Error("the security scheme was dropped")
+	}
+}
+
+func Test_dereferenceDocument_discriminatorMapping(t *testing.T) {
+	doc := map[string]any{
+		"paths": map[string]any{
+			"/pets": map[string]any{"$ref": "#/components/schemas/Pet"},
+		},
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"Pet": map[string]any{
+					"discriminator": map[string]any{
+						"propertyName": "kind",
+						"mapping":      map[string]any{"dog": "#/components/schemas/Dog"},
+					},
+				},
+				"Dog":    map[string]any{"type": "object"},
+				"Unused": map[string]any{"type": "string"},
+			},
+		},
+	}
+	dereferenceDocument(doc)
+
+	schemas, _ := lookup(doc, "components", "schemas").(map[string]any)
+	if _, ok := schemas["Dog"]; !ok {
+		t.Error("Dog, referenced by a discriminator mapping, was dropped")
+	}
+	if _, ok := schemas["Unused"]; ok {
+		t.Error("Unused was kept")
+	}
+}
+
+func Test_Bundle_yamlOrder(t *testing.T) {
+	data, err := loadBundleSpec(t).Bundle(BundleInline, "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" && line[0] != ' ' {
+			keys = append(keys, strings.TrimSuffix(line, ":"))
+		}
+	}
+	if len(keys) < 4 || !reflect.DeepEqual(keys[:3], []string{"openapi: 3.0.3", "info", "paths"}) {
+		t.Errorf("top-level fields = %v, want openapi, info and paths first", keys)
+	}
+}
@@ -0,0 +1,284 @@
+package parser
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/xseman/openapi-generator/internal/codegen"
+)
+
+// httpMethods lists the HTTP methods processed for a path item, in output order.
+var httpMethods = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS", "HEAD"}
+
+// callbacksToCodegen converts the callbacks of an operation to CodegenCallbacks.
+// Every callback request becomes a nested CodegenOperation flagged with IsCallbackRequest,
+// whose Path holds the runtime expression of the callback URL.
+func (p *Parser) callbacksToCodegen(parent *codegen.CodegenOperation, callbacks openapi3.Callbacks) []*codegen.CodegenCallback {
+	if len(callbacks) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(callbacks))
+	for name := range callbacks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result []*codegen.CodegenCallback
+	for _, name := range names {
+		callbackRef := callbacks[name]
+		if callbackRef == nil || callbackRef.Value == nil {
+			continue
+		}
+
+		cb := &codegen.CodegenCallback{
+			Name:             name,
+			Classname:        toPascalCase(parent.OperationIdCamelCase+"_"+name) + "Callback",
+			VendorExtensions: convertExtensions(callbackRef.Value.Extensions),
+		}
+
+		// Sort expressions for deterministic output
+		pathItems := callbackRef.Value.Map()
+		expressions := make([]string, 0, len(pathItems))
+		for expression := range pathItems {
+			expressions = append(expressions, expression)
+		}
+		sort.Strings(expressions)
+
+		for _, expression := range expressions {
+			pathItem := pathItems[expression]
+			if pathItem == nil {
+				continue
+			}
+
+			for _, method := range httpMethods {
+				op := pathItem.GetOperation(method)
+				if op == nil {
+					continue
+				}
+
+				request := p.operationToCodegen(expression, method, op, pathItem)
+				request.IsCallbackRequest = true
+				request.BaseName = parent.BaseName
+				cb.Operations = append(cb.Operations, request)
+			}
+		}
+
+		result = append(result, cb)
+	}
+
+	return result
+}
+
+// linksToCodegen converts the links of a response to CodegenLinks.
+// Targets are resolved later by resolveLinks, once all operations are known.
+func (p *Parser) linksToCodegen(links openapi3.Links) []*codegen.CodegenLink {
+	if len(links) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(links))
+	for name := range links {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result []*codegen.CodegenLink
+	for _, name := range names {
+		linkRef := links[name]
+		if linkRef == nil || linkRef.Value == nil {
+			continue
+		}
+		link := linkRef.Value
+
+		cl := &codegen.CodegenLink{
+			Name:             name,
+			OperationId:      link.OperationID,
+			OperationRef:     link.OperationRef,
+			Description:      link.Description,
+			VendorExtensions: convertExtensions(link.Extensions),
+		}
+
+		// Sort parameter names for deterministic output
+		paramNames := make([]string, 0, len(link.Parameters))
+		for paramName := range link.Parameters {
+			paramNames = append(paramNames, paramName)
+		}
+		sort.Strings(paramNames)
+
+		for _, paramName := range paramNames {
+			cl.Parameters = append(cl.Parameters, linkParameterToCodegen(paramName, link.Parameters[paramName]))
+		}
+		cl.HasParameters = len(cl.Parameters) > 0
+
+		if link.RequestBody != nil {
+			cl.RequestBody = linkParameterToCodegen("body", link.RequestBody)
+		}
+
+		if link.Server != nil {
+			cl.Server = serverToCodegen(link.Server)
+		}
+
+		result = append(result, cl)
+	}
+
+	return result
+}
+
+// linkParameterToCodegen converts a link parameter value to a CodegenLinkParameter.
+// The parameter name may carry a location prefix (e.g. "path.id") to disambiguate
+// parameters sharing a name; the prefix is stripped from Name.
+func linkParameterToCodegen(name string, value any) *codegen.CodegenLinkParameter {
+	lp := &codegen.CodegenLinkParameter{Name: name}
+	for _, in := range []string{"path.", "query.", "header.", "cookie."} {
+		if strings.HasPrefix(name, in) {
+			lp.Name = strings.TrimPrefix(name, in)
+			break
+		}
+	}
+
+	expression, ok := value.(string)
+	if !ok || !strings.HasPrefix(expression, "$") {
+		// Constant values are passed through as-is
+		lp.IsConstant = true
+		if ok {
+			lp.Expression = expression
+		} else {
+			lp.Expression = fmt.Sprintf("%v", value)
+		}
+		return lp
+	}
+
+	lp.Expression = expression
+	parseRuntimeExpression(lp, expression)
+	return lp
+}
+
+// parseRuntimeExpression splits a runtime expression into its parts.
+// See https://spec.openapis.org/oas/v3.0.3#runtime-expressions
+func parseRuntimeExpression(lp *codegen.CodegenLinkParameter, expression string) {
+	switch expression {
+	case "$url":
+		lp.Source = "url"
+		return
+	case "$method":
+		lp.Source = "method"
+		return
+	case "$statusCode":
+		lp.Source = "statusCode"
+		return
+	}
+
+	source, rest, found := strings.Cut(strings.TrimPrefix(expression, "$"), ".")
+	if !found || (source != "request" && source != "response") {
+		// Unknown expression, keep it as a constant
+		lp.IsConstant = true
+		return
+	}
+
+	if rest == "body" || strings.HasPrefix(rest, "body#") {
+		lp.Source = source
+		lp.IsRequest = source == "request"
+		lp.IsResponse = source == "response"
+		lp.Location = "body"
+		lp.IsBody = true
+		lp.Pointer = strings.TrimPrefix(strings.TrimPrefix(rest, "body"), "#")
+		lp.PointerSegments = splitJSONPointer(lp.Pointer)
+		return
+	}
+
+	// Responses have no path or query parameters
+	location, reference, _ := strings.Cut(rest, ".")
+	valid := location == "header" || (source == "request" && (location == "path" || location == "query"))
+	if !valid || reference == "" {
+		lp.IsConstant = true
+		return
+	}
+	lp.Source = source
+	lp.IsRequest = source == "request"
+	lp.IsResponse = source == "response"
+	lp.Location = location
+	lp.Reference = reference
+	lp.IsHeader = location == "header"
+	lp.IsPathParameter = location == "path"
+	lp.IsQueryParameter = location == "query"
+}
+
+// resolveLinks fills in the target operation of every link found in the given operations.
+func resolveLinks(operations []*codegen.CodegenOperation) {
+	byID := make(map[string]*codegen.CodegenOperation)
+	byPath := make(map[string]*codegen.CodegenOperation)
+	for _, op := range operations {
+		// Operations generated into several APIs link to their first copy
+		key := strings.ToLower(op.HttpMethod) + " " + op.Path
+		if _, ok := byPath[key]; ok {
+			continue
+		}
+		// Links target the first operation with an ID, not the duplicates renamed after it
+		for _, id := range []string{op.OperationIdOriginal, op.OperationId} {
+			if _, ok := byID[id]; id != "" && !ok {
+				byID[id] = op
+			}
+		}
+		byPath[key] = op
+	}
+
+	for _, op := range operations {
+		for _, resp := range op.Responses {
+			for _, link := range resp.Links {
+				target := byID[link.OperationId]
+				if target == nil && link.OperationRef != "" {
+					if path, method, ok := parseOperationRef(link.OperationRef); ok {
+						target = byPath[method+" "+path]
+					}
+				}
+				if target == nil {
+					continue
+				}
+
+				link.TargetPath = target.Path
+				link.TargetHttpMethod = target.HttpMethod
+				link.TargetNickname = target.Nickname
+				link.TargetBaseName = target.BaseName
+				link.IsResolved = true
+			}
+		}
+	}
+}
+
+// parseOperationRef extracts the path and lowercase method from an operationRef
+// such as "#/paths/~1pets~1{petId}/get". External references are resolved
+// against the current document using their fragment only.
+func parseOperationRef(ref string) (path, method string, ok bool) {
+	_, fragment, found := strings.Cut(ref, "#")
+	if !found {
+		return "", "", false
+	}
+	if unescaped, err := url.PathUnescape(fragment); err == nil {
+		fragment = unescaped
+	}
+
+	segments := splitJSONPointer(fragment)
+	if len(segments) != 3 || segments[0] != "paths" {
+		return "", "", false
+	}
+	return segments[1], strings.ToLower(segments[2]), true
+}
+
+// splitJSONPointer splits a JSON pointer into unescaped reference tokens.
+func splitJSONPointer(pointer string) []string {
+	pointer = strings.TrimPrefix(pointer, "/")
+	if pointer == "" {
+		return nil
+	}
+
+	segments := strings.Split(pointer, "/")
+	for i, segment := range segments {
+		segment = strings.ReplaceAll(segment, "~1", "/")
+		segments[i] = strings.ReplaceAll(segment, "~0", "~")
+	}
+	return segments
+}
@@ -0,0 +1,202 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xseman/openapi-generator/internal/codegen"
+)
+
+func Test_parseRuntimeExpression_valid(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       codegen.CodegenLinkParameter
+	}{
+		{"$url", codegen.CodegenLinkParameter{Source: "url"}},
+		{"$method", codegen.CodegenLinkParameter{Source: "method"}},
+		{"$statusCode", codegen.CodegenLinkParameter{Source: "statusCode"}},
+		{"$request.path.petId", codegen.CodegenLinkParameter{
+			Source: "request", Location: "path", Reference: "petId", IsRequest: true, IsPathParameter: true,
+		}},
+		{"$request.query.page.size", codegen.CodegenLinkParameter{
+			Source: "request", Location: "query", Reference: "page.size", IsRequest: true, IsQueryParameter: true,
+		}},
+		{"$response.header.Location", codegen.CodegenLinkParameter{
+			Source: "response", Location: "header", Reference: "Location", IsResponse: true, IsHeader: true,
+		}},
+		{"$request.body", codegen.CodegenLinkParameter{
+			Source: "request", Location: "body", IsRequest: true, IsBody: true,
+		}},
+		{"$response.body#/items/0/id", codegen.CodegenLinkParameter{
+			Source: "response", Location: "body", Pointer: "/items/0/id", PointerSegments: []string{"items", "0", "id"},
+			IsResponse: true, IsBody: true,
+		}},
+		{"$response.body#/a~1b/c~0d", codegen.CodegenLinkParameter{
+			Source: "response", Location: "body", Pointer: "/a~1b/c~0d", PointerSegments: []string{"a/b", "c~d"},
+			IsResponse: true, IsBody: true,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			var got codegen.CodegenLinkParameter
+			parseRuntimeExpression(&got, tt.expression)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRuntimeExpression(%q) = %+v, want %+v", tt.expression, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseRuntimeExpression_malformed(t *testing.T) {
+	for _, expression := range []string{
+		"$",
+		"$request",
+		"$request.",
+		"$requestbody",
+		"$request.header",
+		"$request.cookie.session",
+		"$response.path.id",
+		"$response.query.page",
+		"$session.id",
+		"$URL",
+	} {
+		t.Run(expression, func(t *testing.T) {
+			var got codegen.CodegenLinkParameter
+			parseRuntimeExpression(&got, expression)
+			if want := (codegen.CodegenLinkParameter{IsConstant: true}); !reflect.DeepEqual(got, want) {
+				t.Errorf("parseRuntimeExpression(%q) = %+v, want a constant", expression, got)
+			}
+		})
+	}
+}
+
+func Test_linkParameterToCodegen_constants(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  codegen.CodegenLinkParameter
+	}{
+		{"path.id", "fixed", codegen.CodegenLinkParameter{Name: "id", Expression: "fixed", IsConstant: true}},
+		{"limit", 10, codegen.CodegenLinkParameter{Name: "limit", Expression: "10", IsConstant: true}},
+		{"header.X-Id", "$response.header.X-Id", codegen.CodegenLinkParameter{
+			Name: "X-Id", Expression: "$response.header.X-Id",
+			Source: "response", Location: "header", Reference: "X-Id", IsResponse: true, IsHeader: true,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := linkParameterToCodegen(tt.name, tt.value); !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("linkParameterToCodegen(%q, %v) = %+v, want %+v", tt.name, tt.value, *got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseOperationRef(t *testing.T) {
+	tests := []struct {
+		ref          string
+		path, method string
+		ok           bool
+	}{
+		{"#/paths/~1pets~1{petId}/get", "/pets/{petId}", "get", true},
+		{"#/paths/~1pets/POST", "/pets", "post", true},
+		{"https://example.com/openapi.yaml#/paths/~1users/get", "/users", "get", true},
+		{"#/paths/%7E1pets%7E1%7Bid%7D/delete", "/pets/{id}", "delete", true},
+		{"#/paths/~1a~0b/get", "/a~b", "get", true},
+		{"#/paths/~1pets", "", "", false},
+		{"#/paths/~1pets/get/responses", "", "", false},
+		{"#/components/~1pets/get", "", "", false},
+		{"/paths/~1pets/get", "", "", false},
+		{"", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			path, method, ok := parseOperationRef(tt.ref)
+			if path != tt.path || method != tt.method || ok != tt.ok {
+				t.Errorf("parseOperationRef(%q) = %q, %q, %v, want %q, %q, %v", tt.ref, path, method, ok, tt.path, tt.method, tt.ok)
+			}
+		})
+	}
+}
+
+func Test_splitJSONPointer(t *testing.T) {
+	tests := []struct {
+		pointer string
+		want    []string
+	}{
+		{"", nil},
+		{"/", nil},
+		{"/id", []string{"id"}},
+		{"/items/0/id", []string{"items", "0", "id"}},
+		{"items/0", []string{"items", "0"}},
+		{"/a~1b", []string{"a/b"}},
+		{"/a~0b", []string{"a~b"}},
+		// ~01 is an escaped tilde followed by 1, not a slash
+		{"/a~01", []string{"a~1"}},
+		{"/a//b", []string{"a", "", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pointer, func(t *testing.T) {
+			if got := splitJSONPointer(tt.pointer); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitJSONPointer(%q) = %q, want %q", tt.pointer, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetOperations_linksToRenamedDuplicates(t *testing.T) {
+	p := NewParser()
+	p.SkipValidation = true
+	loadTestSpec(t, p, `
+openapi: 3.0.3
+info:
+	title: Links
+	version: "1"
+paths:
+	/pets:
+		post:
+			operationId: addPet
+			responses:
+				"201":
+					description: created
+					links:
+						First:
+							operationId: getPet
+						Second:
+							operationRef: "#/paths/~1pets~1{petId}~1copy/get"
+	/pets/{petId}:
+		get:
+			operationId: getPet
+			responses:
+				"200":
+					description: ok
+	/pets/{petId}/copy:
+		get:
+			operationId: getPet
+			responses:
+				"200":
+					description: ok
+`)
+	byTag, err := p.GetOperations()
+	if err != nil {
+		t.Fatalf("GetOperations: %v", err)
+	}
+
+	var nicknames []string
+	for _, op := range byTag[defaultTag] {
+		nicknames = append(nicknames, op.Nickname)
+	}
+	if want := []string{"addPet", "getPet", "getPet1"}; !reflect.DeepEqual(nicknames, want) {
+		t.Fatalf("nicknames = %q, want %q", nicknames, want)
+	}
+	if want := []string{"Duplicate operation ID 'getPet' in tag 'default', renaming to 'getPet1'"}; !reflect.DeepEqual(p.Warnings, want) {
+		t.Errorf("warnings = %q, want %q", p.Warnings, want)
+	}
+
+	links := byTag[defaultTag][0].Responses[0].Links
+	for i, want := range []string{"getPet", "getPet1"} {
+		if links[i].TargetNickname != want {
+			t.Errorf("link %s targets %q, want %q", links[i].Name, links[i].TargetNickname, want)
+		}
+	}
+}
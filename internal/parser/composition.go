@@ -0,0 +1,302 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/xseman/openapi-generator/internal/codegen"
+)
+
+// applyAllOf resolves the allOf members of a schema into the model.
+// One $ref member becomes the Parent, preferring a member with a discriminator
+// or flagged with x-parent (see the REF_AS_PARENT_IN_ALLOF normalizer rule);
+// its properties end up in AllVars and ParentVars flagged with IsInherited.
+// The other $ref members become Interfaces, and their properties are merged
+// into Vars together with those of the inline members.
+func (p *Parser) applyAllOf(model *codegen.CodegenModel, schema *openapi3.Schema) {
+	// Required properties declared by the composed schema and its members
+	requiredSet := make(map[string]bool)
+	for _, r := range schema.Required {
+		requiredSet[r] = true
+	}
+
+	var parentRef *openapi3.SchemaRef
+	for _, member := range schema.AllOf {
+		if member == nil || member.Value == nil {
+			continue
+		}
+		if member.Ref == "" {
+			for _, r := range member.Value.Required {
+				requiredSet[r] = true
+			}
+			continue
+		}
+		if parentRef == nil || (!isParentSchema(parentRef.Value) && isParentSchema(member.Value)) {
+			parentRef = member
+		}
+	}
+
+	var parentProps []*codegen.CodegenProperty
+	if parentRef != nil {
+		parentProps = p.composedProperties(parentRef.Value, make(map[*openapi3.Schema]bool))
+	}
+
+	vars := model.Vars
+	model.AllOf = make([]string, 0, len(schema.AllOf))
+	for _, member := range schema.AllOf {
+		if member == nil || member.Value == nil {
+			continue
+		}
+		props := p.composedProperties(member.Value, make(map[*openapi3.Schema]bool))
+		if member.Ref != "" {
+			refName := extractRefName(member.Ref)
+			model.AllOf = append(model.AllOf, refName)
+			if member == parentRef {
+				model.Parent = p.toModelName(refName)
+				model.ParentSchema = refName
+				continue
+			}
+			model.Interfaces = append(model.Interfaces, p.toModelName(refName))
+			if lost := copiedMemberLoss(member.Value, append(parentProps[:len(parentProps):len(parentProps)], vars...), props); lost != "" {
+				p.degrade(model.Source, "allOf reference %q is not a parent, %s", member.Ref, lost)
+			}
+		}
+		vars = mergeVars(vars, props)
+	}
+
+	if model.Parent != "" {
+		model.AllParents = append([]string{model.Parent}, model.Interfaces...)
+	} else {
+		model.AllParents = model.Interfaces
+	}
+
+	for _, prop := range vars {
+		if requiredSet[prop.BaseName] {
+			prop.Required = true
+		}
+	}
+
+	// Inherited properties, unless redeclared by the model itself
+	var parentVars []*codegen.CodegenProperty
+	if parentRef != nil {
+		declared := make(map[string]bool, len(vars))
+		for _, prop := range vars {
+			declared[prop.BaseName] = true
+		}
+
+		for _, prop := range parentProps {
+			if declared[prop.BaseName] {
+				continue
+			}
+			if requiredSet[prop.BaseName] && !prop.Required {
+				// Made required by the model, so it is redeclared rather than inherited
+				prop.Required = true
+				vars = append(vars, prop)
+				continue
+			}
+			prop.IsInherited = true
+			parentVars = append(parentVars, prop)
+		}
+	}
+
+	model.Vars = vars
+	model.ParentVars = parentVars
+	model.AllVars = append(append([]*codegen.CodegenProperty{}, parentVars...), vars...)
+	updateModelVars(model)
+}
+
+// copiedMemberLoss describes what copying the properties of an allOf member
+// into a model loses, or returns an empty string when its properties are all
+// the member declares and none of them conflicts with declared.
+func copiedMemberLoss(member *openapi3.Schema, declared, props []*codegen.CodegenProperty) string {
+	switch {
+	case isParentSchema(member):
+		return "its discriminator and subtypes are ignored"
+	case len(member.OneOf) > 0 || len(member.AnyOf) > 0:
+		return "its oneOf/anyOf is ignored"
+	case member.AdditionalProperties.Schema != nil || (member.AdditionalProperties.Has != nil && *member.AdditionalProperties.Has):
+		return "its additionalProperties are ignored"
+	case member.Type != nil && len(member.Type.Slice()) > 0 && !member.Type.Is(openapi3.TypeObject):
+		return fmt.Sprintf("its type %q is ignored", member.Type.Slice()[0])
+	}
+
+	byName := make(map[string]*codegen.CodegenProperty, len(declared))
+	for _, prop := range declared {
+		byName[prop.BaseName] = prop
+	}
+	for _, prop := range props {
+		if existing, ok := byName[prop.BaseName]; ok && existing.DataType != prop.DataType {
+			return fmt.Sprintf("its property %q of type %s is ignored for %s", prop.BaseName, prop.DataType, existing.DataType)
+		}
+	}
+	return ""
+}
+
+// isParentSchema reports whether a schema is meant to be inherited from.
+func isParentSchema(schema *openapi3.Schema) bool {
+	if schema.Discriminator != nil {
+		return true
+	}
+	parent, _ := schema.Extensions["x-parent"].(bool)
+	return parent
+}
+
+// composedProperties returns the properties of a schema, including those of its allOf members.
+func (p *Parser) composedProperties(schema *openapi3.Schema, visited map[*openapi3.Schema]bool) []*codegen.CodegenProperty {
+	if visited[schema] {
+		return nil
+	}
+	visited[schema] = true
+
+	var props []*codegen.CodegenProperty
+	for _, member := range schema.AllOf {
+		if member == nil || member.Value == nil {
+			continue
+		}
+		props = mergeVars(props, p.composedProperties(member.Value, visited))
+	}
+
+	own := p.extractProperties(schema, nil)
+	for _, prop := range props {
+		for _, r := range schema.Required {
+			if prop.BaseName == r {
+				prop.Required = true
+			}
+		}
+	}
+	return mergeVars(props, own)
+}
+
+// mergeVars appends the properties of b that are not already in a.
+// A property required by either side stays required.
+func mergeVars(a, b []*codegen.CodegenProperty) []*codegen.CodegenProperty {
+	index := make(map[string]*codegen.CodegenProperty, len(a))
+	for _, prop := range a {
+		index[prop.BaseName] = prop
+	}
+	for _, prop := range b {
+		if existing, ok := index[prop.BaseName]; ok {
+			existing.Required = existing.Required || prop.Required
+			continue
+		}
+		index[prop.BaseName] = prop
+		a = append(a, prop)
+	}
+	return a
+}
+
+// updateModelVars recomputes the property lists and flags derived from Vars and AllVars.
+func updateModelVars(model *codegen.CodegenModel) {
+	model.RequiredVars = filterRequired(model.Vars)
+	model.OptionalVars = filterOptional(model.Vars)
+	model.ReadOnlyVars = filterReadOnly(model.Vars)
+	model.HasVars = len(model.Vars) > 0
+	model.HasRequired = len(model.RequiredVars) > 0
+	model.HasOptional = len(model.OptionalVars) > 0
+	model.HasReadOnly = len(model.ReadOnlyVars) > 0
+
+	model.Mandatory = nil
+	for _, prop := range model.Vars {
+		if prop.Required {
+			model.Mandatory = append(model.Mandatory, prop.BaseName)
+		}
+	}
+	model.AllMandatory = nil
+	for _, prop := range model.AllVars {
+		if prop.Required {
+			model.AllMandatory = append(model.AllMandatory, prop.BaseName)
+		}
+	}
+}
+
+// composedMembers returns the type of every oneOf/anyOf member, in spec order,
+// and the sorted non-primitive member types to import. Members are only named
+// here: composedSchemas converts them, and records their degradations.
+func (p *Parser) composedMembers(members openapi3.SchemaRefs) (types, models []string) {
+	types = make([]string, 0, len(members))
+	modelSet := make(map[string]bool) // Use map for deduplication
+	for _, ref := range members {
+		var typeName string
+		if ref.Ref != "" {
+			typeName = p.toModelName(extractRefName(ref.Ref))
+		} else if ref.Value != nil {
+			typeName = p.getTypeDeclaration(ref.Value)
+		} else {
+			continue
+		}
+		types = append(types, typeName)
+		if !p.isPrimitiveType(typeName) {
+			modelSet[typeName] = true
+		}
+	}
+
+	models = make([]string, 0, len(modelSet))
+	for modelName := range modelSet {
+		models = append(models, modelName)
+	}
+	sort.Strings(models)
+
+	return types, models
+}
+
+// composedSchemas returns the allOf, oneOf, anyOf and not members of a schema
+// as properties, or nil when the schema has none. Members are named after the
+// model they reference, or after their kind and index when inline.
+func (p *Parser) composedSchemas(schema *openapi3.Schema) *codegen.CodegenComposedSchemas {
+	if len(schema.AllOf) == 0 && len(schema.OneOf) == 0 && len(schema.AnyOf) == 0 && schema.Not == nil {
+		return nil
+	}
+
+	// Inline allOf members are merged into the model, so they are not degraded
+	composed := &codegen.CodegenComposedSchemas{}
+	silent := p.silent
+	p.silent = true
+	composed.AllOf = p.memberProperties("allOf", schema.AllOf)
+	p.silent = silent
+
+	composed.OneOf = p.memberProperties("oneOf", schema.OneOf)
+	composed.AnyOf = p.memberProperties("anyOf", schema.AnyOf)
+	if schema.Not != nil && schema.Not.Value != nil {
+		composed.Not = p.schemaRefToProperty("not", schema.Not, false)
+		composed.ExcludesNot = p.isExcludable(schema, composed.Not)
+	}
+	return composed
+}
+
+// memberProperties converts the members of an allOf, oneOf or anyOf to properties.
+func (p *Parser) memberProperties(kind string, members openapi3.SchemaRefs) []*codegen.CodegenProperty {
+	var props []*codegen.CodegenProperty
+	for i, member := range members {
+		if member == nil || member.Value == nil {
+			continue
+		}
+		name := kind + strconv.Itoa(i)
+		if member.Ref != "" {
+			name = extractRefName(member.Ref)
+		}
+		props = append(props, p.schemaRefToProperty(name, member, false))
+	}
+	return props
+}
+
+// ignoresNot reports whether the not member of composed schemas is dropped
+// from the generated type, instead of excluded from its union.
+func ignoresNot(composed *codegen.CodegenComposedSchemas) bool {
+	return composed != nil && composed.Not != nil && !composed.ExcludesNot
+}
+
+// isExcludable reports whether the not member of a composed schema can be
+// generated as an exclusion from its oneOf/anyOf union: it must be a literal
+// enum or a primitive type, as excluded models could no longer be converted.
+// Generators read the result from CodegenComposedSchemas.ExcludesNot.
+func (p *Parser) isExcludable(schema *openapi3.Schema, not *codegen.CodegenProperty) bool {
+	if len(schema.OneOf) == 0 && len(schema.AnyOf) == 0 {
+		return false
+	}
+	if not.IsEnum {
+		return true
+	}
+	return !not.IsModel && !not.IsContainer && not.DataType != p.anyType()
+}
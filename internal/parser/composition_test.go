@@ -0,0 +1,477 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xseman/openapi-generator/internal/codegen"
+)
+
+func TestAllOfDegradations(t *testing.T) {
+	tests := []struct {
+		name   string
+		member string // Schema of the second allOf member, Mixin
+		parent bool   // Whether the first member, Identifiable, is a parent
+		want   string // Expected degradation, empty for none
+	}{
+		{
+			name:   "properties only",
+			member: "type: object\n\t\t\tproperties:\n\t\t\t\tcreatedAt:\n\t\t\t\t\ttype: string",
+		},
+		{
+			name:   "parent",
+			member: "type: object\n\t\t\tdiscriminator:\n\t\t\t\tpropertyName: kind\n\t\t\tproperties:\n\t\t\t\tkind:\n\t\t\t\t\ttype: string",
+		},
+		{
+			name:   "second parent",
+			member: "type: object\n\t\t\tx-parent: true",
+			parent: true,
+			want:   "its discriminator and subtypes are ignored",
+		},
+		{
+			name:   "oneOf",
+			member: "oneOf:\n\t\t\t\t- type: string\n\t\t\t\t- type: integer",
+			want:   "its oneOf/anyOf is ignored",
+		},
+		{
+			name:   "additionalProperties",
+			member: "type: object\n\t\t\tadditionalProperties:\n\t\t\t\ttype: string",
+			want:   "its additionalProperties are ignored",
+		},
+		{
+			name:   "non-object type",
+			member: "type: string",
+			want:   `its type "string" is ignored`,
+		},
+		{
+			name:   "conflicting property",
+			member: "type: object\n\t\t\tproperties:\n\t\t\t\tid:\n\t\t\t\t\ttype: integer",
+			want:   `its property "id" of type integer is ignored for string`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identifiable := "type: object"
+			if tt.parent {
+				identifiable += "\n\t\t\tx-parent: true"
+			}
+			p := loadTestSpec(t, nil, `
+openapi: 3.0.3
+info:
+	title: AllOf
+	version: "1"
+paths: {}
+components:
+	schemas:
+		Identifiable:
+			`+identifiable+`
+			properties:
+				id:
+					type: string
+		Mixin:
+			`+tt.member+`
+		Pet:
+			allOf:
+				- $ref: "#/components/schemas/Identifiable"
+				- $ref: "#/components/schemas/Mixin"
+				- type: object
+					properties:
+						name:
+							type: string
+`)
+			if _, err := p.GetModels(); err != nil {
+				t.Fatalf("GetModels: %v", err)
+			}
+
+			var got []string
+			for _, d := range p.Degradations {
+				if strings.HasSuffix(d.Source.Pointer, "/Pet") {
+					got = append(got, d.Message)
+				}
+			}
+			switch {
+			case tt.want == "" && len(got) > 0:
+				t.Errorf("degradations = %q, want none", got)
+			case tt.want != "" && (len(got) != 1 || !strings.Contains(got[0], tt.want)):
+				t.Errorf("degradations = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComposedSchemas(t *testing.T) {
+	p := loadTestSpec(t, nil, `
+openapi: 3.0.3
+info:
+	title: Composed
+	version: "1"
+paths: {}
+components:
+	schemas:
+		Pet:
+			type: object
+			properties:
+				name:
+					type: string
+		Merged:
+			allOf:
+				- $ref: "#/components/schemas/Pet"
+				- type: object
+					properties:
+						age:
+							type: integer
+		Choice:
+			oneOf:
+				- type: string
+				- type: object
+					properties:
+						x:
+							type: string
+			not:
+				type: string
+				enum: [""]
+		NotPet:
+			anyOf:
+				- type: string
+				- $ref: "#/components/schemas/Pet"
+			not:
+				$ref: "#/components/schemas/Pet"
+`)
+	models, err := p.GetModels()
+	if err != nil {
+		t.Fatalf("GetModels: %v", err)
+	}
+	byName := make(map[string]*codegen.CodegenModel)
+	for _, model := range models {
+		byName[model.Name] = model
+	}
+
+	if composed := byName["Merged"].ComposedSchemas; composed == nil || len(composed.AllOf) != 2 {
+		t.Fatalf("Merged composed schemas = %+v", composed)
+	}
+	if composed := byName["Choice"].ComposedSchemas; !composed.ExcludesNot {
+		t.Error("Choice: the enum not member is not excluded")
+	}
+	if composed := byName["NotPet"].ComposedSchemas; composed.ExcludesNot {
+		t.Error("NotPet: the model not member is excluded")
+	}
+
+	var got []string
+	for _, d := range p.Degradations {
+		got = append(got, d.Source.Pointer+": "+d.Message)
+	}
+	want := []string{
+		"#/components/schemas/Choice/oneOf/1: inline object schema is generated as AnyType",
+		"#/components/schemas/NotPet: not constraint is ignored",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("degradations = %q, want %q", got, want)
+	}
+}
+
+// modelsByName converts the models of a spec and indexes them by name.
+func modelsByName(t *testing.T, p *Parser) map[string]*codegen.CodegenModel {
+	t.Helper()
+	models, err := p.GetModels()
+	if err != nil {
+		t.Fatalf("GetModels: %v", err)
+	}
+	byName := make(map[string]*codegen.CodegenModel, len(models))
+	for _, model := range models {
+		byName[model.Name] = model
+	}
+	return byName
+}
+
+// varNames returns the base names of properties.
+func varNames(props []*codegen.CodegenProperty) []string {
+	names := make([]string, 0, len(props))
+	for _, prop := range props {
+		names = append(names, prop.BaseName)
+	}
+	return names
+}
+
+func Test_applyAllOf_parentAndInterfaces(t *testing.T) {
+	p := loadTestSpec(t, nil, `
+openapi: 3.0.3
+info:
+	title: AllOf
+	version: "1"
+paths: {}
+components:
+	schemas:
+		Named:
+			type: object
+			required: [name]
+			properties:
+				name:
+					type: string
+		Animal:
+			type: object
+			discriminator:
+				propertyName: kind
+			properties:
+				kind:
+					type: string
+				age:
+					type: integer
+		Dog:
+			required: [age]
+			allOf:
+				- $ref: "#/components/schemas/Named"
+				- $ref: "#/components/schemas/Animal"
+				- type: object
+					required: [bark]
+					properties:
+						bark:
+							type: boolean
+`)
+	dog := modelsByName(t, p)["Dog"]
+
+	// The member with a discriminator wins over the first one
+	if dog.Parent != "Animal" || dog.ParentSchema != "Animal" {
+		t.Errorf("parent = %q (%q), want Animal", dog.Parent, dog.ParentSchema)
+	}
+	if !reflect.DeepEqual(dog.Interfaces, []string{"Named"}) || !reflect.DeepEqual(dog.AllParents, []string{"Animal", "Named"}) {
+		t.Errorf("interfaces = %v, all parents = %v", dog.Interfaces, dog.AllParents)
+	}
+	if !reflect.DeepEqual(dog.AllOf, []string{"Named", "Animal"}) {
+		t.Errorf("allOf = %v", dog.AllOf)
+	}
+
+	// age is inherited, but redeclared as the model makes it required
+	if got := varNames(dog.Vars); !reflect.DeepEqual(got, []string{"name", "bark", "age"}) {
+		t.Errorf("vars = %v", got)
+	}
+	if got := varNames(dog.ParentVars); !reflect.DeepEqual(got, []string{"kind"}) || !dog.ParentVars[0].IsInherited {
+		t.Errorf("parent vars = %v", got)
+	}
+	if got := varNames(dog.AllVars); !reflect.DeepEqual(got, []string{"kind", "name", "bark", "age"}) {
+		t.Errorf("all vars = %v", got)
+	}
+	if !reflect.DeepEqual(dog.Mandatory, []string{"name", "bark", "age"}) || !reflect.DeepEqual(dog.AllMandatory, dog.Mandatory) {
+		t.Errorf("mandatory = %v, all mandatory = %v", dog.Mandatory, dog.AllMandatory)
+	}
+	if !dog.HasRequired || dog.HasOptional {
+		t.Errorf("hasRequired = %v, hasOptional = %v", dog.HasRequired, dog.HasOptional)
+	}
+}
+
+func Test_applyAllOf_nestedAndCyclic(t *testing.T) {
+	p := loadTestSpec(t, nil, `
+openapi: 3.0.3
+info:
+	title: AllOf
+	version: "1"
+paths: {}
+components:
+	schemas:
+		Base:
+			type: object
+			properties:
+				id:
+					type: string
+		Timestamped:
+			allOf:
+				- $ref: "#/components/schemas/Base"
+				- type: object
+					properties:
+						createdAt:
+							type: string
+		Node:
+			type: object
+			properties:
+				next:
+					allOf:
+						- $ref: "#/components/schemas/Node"
+		Entry:
+			allOf:
+				- $ref: "#/components/schemas/Timestamped"
+				- $ref: "#/components/schemas/Node"
+`)
+	entry := modelsByName(t, p)["Entry"]
+
+	// The first reference is the parent; its properties include its own allOf
+	if entry.Parent != "Timestamped" {
+		t.Errorf("parent = %q", entry.Parent)
+	}
+	if got := varNames(entry.ParentVars); !reflect.DeepEqual(got, []string{"id", "createdAt"}) {
+		t.Errorf("parent vars = %v", got)
+	}
+	if got := varNames(entry.Vars); !reflect.DeepEqual(got, []string{"next"}) {
+		t.Errorf("vars = %v", got)
+	}
+}
+
+func Test_mergeVars(t *testing.T) {
+	a := []*codegen.CodegenProperty{{BaseName: "id"}, {BaseName: "name", Required: true}}
+	b := []*codegen.CodegenProperty{{BaseName: "id", Required: true}, {BaseName: "age"}}
+
+	merged := mergeVars(a, b)
+	if got := varNames(merged); !reflect.DeepEqual(got, []string{"id", "name", "age"}) {
+		t.Fatalf("merged = %v", got)
+	}
+	if !merged[0].Required || !merged[1].Required || merged[2].Required {
+		t.Errorf("required = %v, %v, %v", merged[0].Required, merged[1].Required, merged[2].Required)
+	}
+	if merged[0] != a[0] {
+		t.Error("the existing property was replaced")
+	}
+}
+
+func Test_composedMembers_anyOfLikeOneOf(t *testing.T) {
+	p := loadTestSpec(t, nil, `
+openapi: 3.0.3
+info:
+	title: Unions
+	version: "1"
+paths: {}
+components:
+	schemas:
+		Cat:
+			type: object
+			properties:
+				name:
+					type: string
+		Dog:
+			type: object
+			properties:
+				name:
+					type: string
+		OneOfPet:
+			oneOf:
+				- $ref: "#/components/schemas/Dog"
+				- $ref: "#/components/schemas/Cat"
+				- type: string
+				- type: integer
+		AnyOfPet:
+			anyOf:
+				- $ref: "#/components/schemas/Dog"
+				- $ref: "#/components/schemas/Cat"
+				- type: string
+				- type: integer
+`)
+	byName := modelsByName(t, p)
+	oneOf, anyOf := byName["OneOfPet"], byName["AnyOfPet"]
+
+	wantTypes := []string{"Dog", "Cat", "string", "integer"}
+	if !oneOf.HasOneOf || !reflect.DeepEqual(oneOf.OneOf, wantTypes) {
+		t.Errorf("oneOf = %v, want %v", oneOf.OneOf, wantTypes)
+	}
+	if !anyOf.HasAnyOf || !reflect.DeepEqual(anyOf.AnyOf, oneOf.OneOf) {
+		t.Errorf("anyOf = %v, want %v", anyOf.AnyOf, oneOf.OneOf)
+	}
+	if anyOf.HasOneOf || oneOf.HasAnyOf {
+		t.Error("a union is flagged with the other kind")
+	}
+	if !reflect.DeepEqual(anyOf.AnyOfModels, oneOf.OneOfModels) {
+		t.Errorf("anyOf models = %v, oneOf models = %v", anyOf.AnyOfModels, oneOf.OneOfModels)
+	}
+	if models := strings.Join(oneOf.OneOfModels, ","); models != "Cat,Dog" {
+		t.Errorf("imported members = %v, want sorted models without primitives", oneOf.OneOfModels)
+	}
+}
+
+func Test_schemaToProperty_notConstraint(t *testing.T) {
+	p := newGoTypeParser()
+	loadTestSpec(t, p, `openapi: 3.0.3
+info:
+	title: Not
+	version: "1"
+paths: {}
+components:
+	schemas:
+		Holder:
+			type: object
+			properties:
+				choice:
+					anyOf:
+						- type: string
+						- type: integer
+					not:
+						type: string
+						enum: [""]
+				plain:
+					type: string
+					not:
+						enum: [none]
+				union:
+					oneOf:
+						- type: string
+						- type: integer
+					not:
+						type: object
+						properties:
+							x:
+								type: string
+`)
+	holder := modelsByName(t, p)["Holder"]
+	props := make(map[string]*codegen.CodegenProperty)
+	for _, prop := range holder.Vars {
+		props[prop.BaseName] = prop
+	}
+	if composed := props["choice"].ComposedSchemas; composed == nil || !composed.ExcludesNot {
+		t.Errorf("choice composed schemas = %+v, want the enum not member excluded", composed)
+	}
+
+	var got []string
+	for _, d := range p.Degradations {
+		if d.Message == "not constraint is ignored" {
+			got = append(got, d.Source.Pointer)
+		}
+	}
+	want := []string{
+		"#/components/schemas/Holder/properties/plain",
+		"#/components/schemas/Holder/properties/union",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ignored not constraints = %q, want %q", got, want)
+	}
+}
+
+func Test_composedSchemas_membersDegradedOnce(t *testing.T) {
+	p := newGoTypeParser()
+	loadTestSpec(t, p, `openapi: 3.0.3
+info:
+	title: Members
+	version: "1"
+paths: {}
+components:
+	schemas:
+		Union:
+			oneOf:
+				- type: string
+				- type: object
+					properties:
+						x:
+							type: string
+			anyOf:
+				- type: object
+					properties:
+						y:
+							type: string
+				- type: integer
+`)
+	union := modelsByName(t, p)["Union"]
+	if want := []string{"string", "interface{}"}; !reflect.DeepEqual(union.OneOf, want) {
+		t.Errorf("oneOf = %v, want %v", union.OneOf, want)
+	}
+	if composed := union.ComposedSchemas; composed == nil || len(composed.OneOf) != 2 || len(composed.AnyOf) != 2 {
+		t.Fatalf("composed schemas = %+v, want every member", composed)
+	}
+
+	var got []string
+	for _, d := range p.Degradations {
+		got = append(got, d.Source.Pointer+": "+d.Message)
+	}
+	want := []string{
+		"#/components/schemas/Union/oneOf/1: inline object schema is generated as interface{}",
+		"#/components/schemas/Union/anyOf/0: inline object schema is generated as interface{}",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("degradations = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/xseman/openapi-generator/internal/codegen"
+)
+
+// schemaRefToProperty converts a possibly referenced schema to a CodegenProperty.
+// A reference to a component schema becomes a property typed with the model name;
+// a reference to an enum component uses the shared enum model instead of an
+// inline enum. Other references, such as whole external files, are inlined.
+func (p *Parser) schemaRefToProperty(name string, schemaRef *openapi3.SchemaRef, required bool) *codegen.CodegenProperty {
+	if schemaRef.Ref == "" || !p.isComponentRef(schemaRef.Ref) {
+		return p.schemaToProperty(name, schemaRef.Value, required)
+	}
+
+	schema := schemaRef.Value
+	prop := p.schemaToProperty(name, &openapi3.Schema{
+		Description: schema.Description,
+		Title:       schema.Title,
+		Nullable:    schema.Nullable,
+		ReadOnly:    schema.ReadOnly,
+		WriteOnly:   schema.WriteOnly,
+		Deprecated:  schema.Deprecated,
+	}, required)
+
+	modelName := p.toModelName(extractRefName(schemaRef.Ref))
+	prop.OpenApiType = ""
+	prop.IsAnyType = false
+	prop.IsModel = true
+	prop.DataType = modelName
+	prop.Datatype = modelName
+	prop.DatatypeWithEnum = modelName
+	prop.BaseType = modelName
+	prop.ComplexType = modelName
+	prop.Example = ""
+	prop.Source = p.sourceOf(schema)
+
+	if len(schema.Enum) > 0 {
+		prop.IsEnumRef = true
+		prop.AllowableValues = p.enumAllowableValues(schema)
+		prop.IsNullable = prop.IsNullable || hasNullEnumValue(schema)
+	}
+
+	return prop
+}
+
+// isComponentRef reports whether a reference names a component schema, which is
+// generated as a model.
+func (p *Parser) isComponentRef(ref string) bool {
+	if p.Doc == nil || p.Doc.Components == nil {
+		return false
+	}
+	_, ok := p.Doc.Components.Schemas[extractRefName(ref)]
+	return ok
+}
+
+// setContainerType fills the type information of an array, set or map property
+// from its items, which must already be set.
+func (p *Parser) setContainerType(prop *codegen.CodegenProperty) {
+	prop.ContainerTypeMapped = p.getSchemaType(prop.ContainerType, "")
+
+	if prop.Items == nil {
+		prop.BaseType = p.anyType()
+	} else {
+		prop.BaseType = prop.Items.BaseType
+		if prop.BaseType == "" {
+			prop.BaseType = prop.Items.DataType
+		}
+		prop.ComplexType = prop.Items.ComplexType
+
+		// Innermost non-container items, for templates handling nested containers
+		prop.MostInnerItems = prop.Items
+		if prop.Items.IsContainer && prop.Items.MostInnerItems != nil {
+			prop.MostInnerItems = prop.Items.MostInnerItems
+		}
+	}
+
+	prop.DataType = p.getPropertyTypeDeclaration(prop)
+	prop.Datatype = prop.DataType
+}
+
+// getPropertyTypeDeclaration returns the type declaration of a property,
+// delegating to the generator when one is configured.
+func (p *Parser) getPropertyTypeDeclaration(prop *codegen.CodegenProperty) string {
+	if p.TypeDeclarationFunc != nil {
+		return p.TypeDeclarationFunc(prop)
+	}
+
+	// Language-neutral notation
+	inner := p.anyType()
+	if prop.Items != nil {
+		inner = p.getPropertyTypeDeclaration(prop.Items)
+	}
+	switch {
+	case prop.IsArray && prop.UniqueItems:
+		return "set<" + inner + ">"
+	case prop.IsArray:
+		return "array<" + inner + ">"
+	case prop.IsMap:
+		return "map<string, " + inner + ">"
+	}
+	return prop.DataType
+}
@@ -0,0 +1,156 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xseman/openapi-generator/internal/codegen"
+)
+
+func Test_setContainerType_nested(t *testing.T) {
+	p := loadTestSpec(t, nil, `
+openapi: 3.0.3
+info:
+	title: Containers
+	version: "1"
+paths: {}
+components:
+	schemas:
+		Tag:
+			type: object
+			properties:
+				label:
+					type: string
+		Board:
+			type: object
+			properties:
+				grid:
+					type: array
+					items:
+						type: array
+						uniqueItems: true
+						items:
+							$ref: "#/components/schemas/Tag"
+				index:
+					type: object
+					additionalProperties:
+						type: array
+						items:
+							type: integer
+				labels:
+					type: array
+					items:
+						type: string
+`)
+	board := modelsByName(t, p)["Board"]
+
+	want := map[string]string{
+		"grid":   "array<set<Tag>>",
+		"index":  "map<string, array<integer>>",
+		"labels": "array<string>",
+	}
+	for _, prop := range board.Vars {
+		if prop.DataType != want[prop.BaseName] {
+			t.Errorf("%s DataType = %q, want %q", prop.BaseName, prop.DataType, want[prop.BaseName])
+		}
+		if !prop.IsContainer || prop.MostInnerItems == nil || prop.MostInnerItems.IsContainer {
+			t.Errorf("%s innermost items = %+v", prop.BaseName, prop.MostInnerItems)
+		}
+	}
+
+	grid := board.Vars[0]
+	if grid.BaseType != "Tag" || grid.ComplexType != "Tag" || !grid.MostInnerItems.IsModel {
+		t.Errorf("grid base type = %q, complex type = %q", grid.BaseType, grid.ComplexType)
+	}
+	if grid.Items.DataType != "set<Tag>" || !grid.Items.UniqueItems {
+		t.Errorf("grid items = %q", grid.Items.DataType)
+	}
+	if !reflect.DeepEqual(board.Imports, []string{"Tag"}) {
+		t.Errorf("imports = %v, want the nested model", board.Imports)
+	}
+}
+
+func Test_schemaRefToProperty_enumComponent(t *testing.T) {
+	p := loadTestSpec(t, nil, `
+openapi: 3.0.3
+info:
+	title: Enums
+	version: "1"
+paths:
+	/pets:
+		get:
+			operationId: listPets
+			parameters:
+				- name: status
+					in: query
+					schema:
+						$ref: "#/components/schemas/Status"
+				- name: mode
+					in: query
+					schema:
+						type: string
+						enum: [fast, slow]
+			responses:
+				"200":
+					description: ok
+components:
+	schemas:
+		Status:
+			type: string
+			nullable: true
+			enum: [available, sold]
+		Pet:
+			type: object
+			properties:
+				status:
+					$ref: "#/components/schemas/Status"
+				history:
+					type: array
+					items:
+						$ref: "#/components/schemas/Status"
+				mode:
+					type: string
+					enum: [fast, slow]
+`)
+	props := make(map[string]*codegen.CodegenProperty)
+	for _, prop := range modelsByName(t, p)["Pet"].Vars {
+		props[prop.BaseName] = prop
+	}
+
+	status := props["status"]
+	if !status.IsEnumRef || status.IsEnum || status.DataType != "Status" || status.DatatypeWithEnum != "Status" {
+		t.Errorf("status = IsEnumRef %v, IsEnum %v, %s/%s; want a reference to the Status enum",
+			status.IsEnumRef, status.IsEnum, status.DataType, status.DatatypeWithEnum)
+	}
+	if !status.IsNullable {
+		t.Error("status is not nullable like the Status enum")
+	}
+	if vars, _ := status.AllowableValues["enumVars"].([]map[string]any); len(vars) != 2 {
+		t.Errorf("status enumVars = %v, want the values of Status", status.AllowableValues["enumVars"])
+	}
+	if items := props["history"].Items; items == nil || !items.IsEnumRef || items.DataType != "Status" {
+		t.Errorf("history items = %+v, want a reference to the Status enum", items)
+	}
+	if props["history"].DataType != "array<Status>" {
+		t.Errorf("history = %s, want array<Status>", props["history"].DataType)
+	}
+	if mode := props["mode"]; !mode.IsEnum || mode.IsEnumRef || mode.EnumName != "ModeEnum" {
+		t.Errorf("mode = IsEnum %v, IsEnumRef %v, %s; want an inline enum", mode.IsEnum, mode.IsEnumRef, mode.EnumName)
+	}
+
+	ops, err := p.GetOperations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := make(map[string]*codegen.CodegenParameter)
+	for _, param := range ops[defaultTag][0].AllParams {
+		params[param.BaseName] = param
+	}
+	if param := params["status"]; !param.IsEnumRef || param.IsEnum || param.DataType != "Status" {
+		t.Errorf("status parameter = IsEnumRef %v, IsEnum %v, %s; want a reference to the Status enum",
+			param.IsEnumRef, param.IsEnum, param.DataType)
+	}
+	if param := params["mode"]; !param.IsEnum || param.IsEnumRef {
+		t.Errorf("mode parameter = IsEnum %v, IsEnumRef %v; want an inline enum", param.IsEnum, param.IsEnumRef)
+	}
+}
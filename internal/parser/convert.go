@@ -0,0 +1,250 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConvertOptions configures the conversion of a spec.
+type ConvertOptions struct {
+	To       string // OpenAPI version to convert to, only "3.0" is supported
+	Format   string // "json" or "yaml"
+	SortKeys bool   // Sort all object keys, instead of keeping the original order
+}
+
+// Convert loads a spec and returns it in another format or OpenAPI version.
+// Swagger 2.0 specs are converted to OpenAPI 3.0 like for generation; other
+// specs keep their content and key order, only the format changes.
+func (p *Parser) Convert(input string, opts ConvertOptions) ([]byte, error) {
+	if opts.To != "" && opts.To != "3.0" {
+		return nil, fmt.Errorf("unsupported target version %q (expected 3.0)", opts.To)
+	}
+
+	data, err := p.readSpec(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.LoadSpec(input); err != nil {
+		return nil, err
+	}
+
+	var node yaml.Node
+	if isSwagger2(data) {
+		converted, err := json.Marshal(p.Doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize spec: %w", err)
+		}
+		if err := yaml.Unmarshal(converted, &node); err != nil {
+			return nil, fmt.Errorf("failed to serialize spec: %w", err)
+		}
+		orderDocumentNode(&node)
+	} else {
+		if !strings.HasPrefix(p.Doc.OpenAPI, "3.0") {
+			return nil, fmt.Errorf("cannot convert OpenAPI %s to 3.0", p.Doc.OpenAPI)
+		}
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return nil, fmt.Errorf("failed to parse spec: %w", err)
+		}
+	}
+
+	if opts.SortKeys {
+		sortNode(&node)
+	}
+	return encodeNode(&node, opts.Format)
+}
+
+// readSpec reads the raw content of a spec file or URL.
+func (p *Parser) readSpec(input string) ([]byte, error) {
+	if strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "https://") {
+		return p.fetchSpec(input)
+	}
+	data, err := os.ReadFile(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return data, nil
+}
+
+// documentKeyOrder is the order of the top-level fields of an encoded document.
+var documentKeyOrder = []string{
+	"openapi", "info", "jsonSchemaDialect", "servers", "security", "tags",
+	"externalDocs", "paths", "webhooks", "components",
+}
+
+// orderDocumentNode puts the top-level fields of a document in their usual
+// order, followed by the other fields in their current order.
+func orderDocumentNode(node *yaml.Node) {
+	root := documentRoot(node)
+	if root.Kind != yaml.MappingNode {
+		return
+	}
+	rank := func(i int) int {
+		if r := slices.Index(documentKeyOrder, root.Content[i*2].Value); r >= 0 {
+			return r
+		}
+		return len(documentKeyOrder)
+	}
+	pairs := make([]int, len(root.Content)/2)
+	for i := range pairs {
+		pairs[i] = i
+	}
+	sort.SliceStable(pairs, func(a, b int) bool { return rank(pairs[a]) < rank(pairs[b]) })
+
+	content := make([]*yaml.Node, 0, len(root.Content))
+	for _, i := range pairs {
+		content = append(content, root.Content[i*2], root.Content[i*2+1])
+	}
+	root.Content = content
+}
+
+// sortNode sorts the keys of all mappings below a node.
+func sortNode(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		pairs := make([][2]*yaml.Node, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			pairs = append(pairs, [2]*yaml.Node{node.Content[i], node.Content[i+1]})
+		}
+		sort.SliceStable(pairs, func(a, b int) bool { return pairs[a][0].Value < pairs[b][0].Value })
+		node.Content = node.Content[:0]
+		for _, pair := range pairs {
+			node.Content = append(node.Content, pair[0], pair[1])
+		}
+	}
+	for _, child := range node.Content {
+		sortNode(child)
+	}
+}
+
+func documentRoot(node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		return node.Content[0]
+	}
+	return node
+}
+
+// encodeNode encodes a YAML node tree as "json" or "yaml".
+func encodeNode(node *yaml.Node, format string) ([]byte, error) {
+	switch format {
+	case "json":
+		var buf bytes.Buffer
+		if err := writeJSONNode(&buf, documentRoot(node), ""); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\n")
+		return buf.Bytes(), nil
+	case "yaml":
+		blockStyle(node)
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(node); err != nil {
+			return nil, fmt.Errorf("failed to encode document: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode document: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("invalid format %q (expected json or yaml)", format)
+}
+
+// blockStyle drops the flow and quoting styles of nodes parsed from JSON, so
+// they are written as plain YAML. Literal and folded strings are kept.
+func blockStyle(node *yaml.Node) {
+	if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+		node.Style = 0
+	}
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// writeJSONNode writes a node as indented JSON, keeping the key order.
+func writeJSONNode(buf *bytes.Buffer, node *yaml.Node, indent string) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeJSONNode(buf, node.Content[0], indent)
+	case yaml.AliasNode:
+		return writeJSONNode(buf, node.Alias, indent)
+	case yaml.MappingNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		buf.WriteString("{\n")
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, err := jsonString(node.Content[i].Value)
+			if err != nil {
+				return err
+			}
+			buf.WriteString(indent + "  " + key + ": ")
+			if err := writeJSONNode(buf, node.Content[i+1], indent+"  "); err != nil {
+				return err
+			}
+			if i+2 < len(node.Content) {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent + "}")
+	case yaml.SequenceNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteString("[\n")
+		for i, item := range node.Content {
+			buf.WriteString(indent + "  ")
+			if err := writeJSONNode(buf, item, indent+"  "); err != nil {
+				return err
+			}
+			if i < len(node.Content)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent + "]")
+	default:
+		value, err := overlayValue(node)
+		if err != nil {
+			return err
+		}
+		if s, ok := value.(string); ok {
+			encoded, err := jsonString(s)
+			if err != nil {
+				return err
+			}
+			buf.WriteString(encoded)
+			return nil
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			// Values without JSON equivalent, such as .inf, are kept as strings
+			encoded, _ = json.Marshal(node.Value)
+		}
+		buf.Write(encoded)
+	}
+	return nil
+}
+
+// jsonString encodes a string as JSON, without escaping HTML characters.
+func jsonString(s string) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(buf.String(), "\n"), nil
+}
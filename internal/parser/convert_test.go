@@ -0,0 +1,188 @@
+package parser
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const convertSpec = `info:
+  title: Convert <&>
+  version: "1"
+openapi: 3.0.3
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: |
+            ok
+      operationId: listPets
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        born:
+          type: string
+          example: 2020-01-02
+        weight:
+          type: number
+          example: 1.5
+`
+
+const convertSwagger = `swagger: "2.0"
+info:
+  title: Legacy
+  version: "1"
+host: api.example.com
+basePath: /v1
+schemes: [https]
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      produces: [application/json]
+      responses:
+        "200":
+          description: ok
+          schema:
+            $ref: '#/definitions/Pet'
+definitions:
+  Pet:
+    type: object
+    properties:
+      name:
+        type: string
+`
+
+func Test_Convert_yamlToJSON(t *testing.T) {
+	p := NewParser()
+	got, err := p.Convert(writeTestFile(t, "spec.yaml", convertSpec), ConvertOptions{Format: "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "info": {
+    "title": "Convert <&>",
+    "version": "1"
+  },
+  "openapi": "3.0.3",
+  "paths": {
+    "/pets": {
+      "get": {
+        "responses": {
+          "200": {
+            "description": "ok\n"
+          }
+        },
+        "operationId": "listPets"
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "properties": {
+          "born": {
+            "type": "string",
+            "example": "2020-01-02"
+          },
+          "weight": {
+            "type": "number",
+            "example": 1.5
+          }
+        }
+      }
+    }
+  }
+}
+`
+	if string(got) != want {
+		t.Errorf("Convert() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func Test_Convert_jsonToYAML(t *testing.T) {
+	p := NewParser()
+	input := `{"openapi": "3.0.3", "info": {"title": "T", "version": "1"}, "paths": {}, "x-tags": ["a", "b"]}`
+	got, err := p.Convert(writeTestFile(t, "spec.json", input), ConvertOptions{Format: "yaml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `openapi: 3.0.3
+info:
+  title: T
+  version: "1"
+paths: {}
+x-tags:
+  - a
+  - b
+`
+	if string(got) != want {
+		t.Errorf("Convert() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func Test_Convert_sortKeys(t *testing.T) {
+	p := NewParser()
+	got, err := p.Convert(writeTestFile(t, "spec.yaml", convertSpec), ConvertOptions{Format: "yaml", SortKeys: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, line := range strings.Split(string(got), "\n") {
+		if line != "" && line[0] != ' ' {
+			keys = append(keys, strings.SplitN(line, ":", 2)[0])
+		}
+	}
+	if strings.Join(keys, ",") != "components,info,openapi,paths" {
+		t.Errorf("top-level keys = %v, want them sorted", keys)
+	}
+	if strings.Index(string(got), "operationId") > strings.Index(string(got), "responses") {
+		t.Error("operation keys are not sorted")
+	}
+}
+
+func Test_Convert_swagger2(t *testing.T) {
+	p := NewParser()
+	got, err := p.Convert(writeTestFile(t, "swagger.yaml", convertSwagger), ConvertOptions{To: "3.0", Format: "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(got, &doc); err != nil {
+		t.Fatalf("Convert() is not JSON: %v\n%s", err, got)
+	}
+	if openapi, _ := doc["openapi"].(string); !strings.HasPrefix(openapi, "3.0") {
+		t.Errorf("openapi = %v, want 3.0", doc["openapi"])
+	}
+	if servers, _ := doc["servers"].([]any); len(servers) != 1 || lookup(servers[0], "url") != "https://api.example.com/v1" {
+		t.Errorf("servers = %v, want the Swagger host and base path", doc["servers"])
+	}
+	schema := lookup(doc, "paths", "/pets", "get", "responses", "200", "content", "application/json", "schema", "$ref")
+	if schema != "#/components/schemas/Pet" {
+		t.Errorf("response schema = %v, want a components reference", schema)
+	}
+	if !strings.HasPrefix(string(got), "{\n  \"openapi\"") {
+		t.Errorf("converted document does not start with openapi:\n%s", got)
+	}
+}
+
+func Test_Convert_invalidOptions(t *testing.T) {
+	tests := []struct {
+		opts ConvertOptions
+		want string
+	}{
+		{ConvertOptions{To: "3.1", Format: "json"}, "unsupported target version"},
+		{ConvertOptions{Format: "xml"}, "invalid format"},
+	}
+	for _, tt := range tests {
+		p := NewParser()
+		_, err := p.Convert(writeTestFile(t, "spec.yaml", convertSpec), tt.opts)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Convert(%+v) error = %v, want %q", tt.opts, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"sort"
+
+	"github.com/xseman/openapi-generator/internal/codegen"
+)
+
+// linkModels runs once all models are parsed. It links allOf children to their
+// parent model and completes the discriminator mappings of polymorphic models.
+func (p *Parser) linkModels(models []*codegen.CodegenModel) {
+	bySchemaName := make(map[string]*codegen.CodegenModel, len(models))
+	byClassname := make(map[string]*codegen.CodegenModel, len(models))
+	for _, model := range models {
+		bySchemaName[model.SchemaName] = model
+		byClassname[model.Classname] = model
+	}
+
+	for _, model := range models {
+		if model.ParentSchema == "" {
+			continue
+		}
+		parent := bySchemaName[model.ParentSchema]
+		if parent == nil || parent == model {
+			continue
+		}
+		model.ParentModel = parent
+		parent.Children = append(parent.Children, model)
+		parent.HasChildren = true
+	}
+
+	for _, model := range models {
+		if model.Discriminator != nil {
+			p.inferDiscriminatorMapping(model, byClassname)
+		}
+	}
+}
+
+// inferDiscriminatorMapping adds the implicit mappings of a discriminator.
+// Without an explicit mapping entry, the schema name of every oneOf/anyOf member
+// and of every allOf descendant is used as discriminator value.
+func (p *Parser) inferDiscriminatorMapping(model *codegen.CodegenModel, byClassname map[string]*codegen.CodegenModel) {
+	d := model.Discriminator
+
+	mapped := make(map[string]bool)
+	for _, mm := range d.MappedModels {
+		mm.Model = byClassname[mm.ModelName]
+		mapped[mm.ModelName] = true
+	}
+
+	var candidates []string
+	if schemaRef := p.Doc.Components.Schemas[model.SchemaName]; schemaRef != nil && schemaRef.Value != nil {
+		for _, member := range append(schemaRef.Value.OneOf, schemaRef.Value.AnyOf...) {
+			if member != nil && member.Ref != "" {
+				candidates = append(candidates, extractRefName(member.Ref))
+			}
+		}
+	}
+	for _, child := range descendants(model) {
+		candidates = append(candidates, child.SchemaName)
+	}
+
+	for _, schemaName := range candidates {
+		modelName := p.toModelName(schemaName)
+		if mapped[modelName] {
+			continue
+		}
+		mapped[modelName] = true
+		d.MappedModels = append(d.MappedModels, &codegen.MappedModel{
+			MappingName: schemaName,
+			ModelName:   modelName,
+			Model:       byClassname[modelName],
+		})
+	}
+
+	sort.Slice(d.MappedModels, func(i, j int) bool {
+		return d.MappedModels[i].MappingName < d.MappedModels[j].MappingName
+	})
+	model.HasDiscriminatorWithNonEmptyMapping = len(d.MappedModels) > 0
+
+	// Mark the discriminator property on the model and on every mapped model
+	markDiscriminatorProperty(model, d)
+	for _, mm := range d.MappedModels {
+		if mm.Model != nil && mm.Model != model {
+			markDiscriminatorProperty(mm.Model, d)
+		}
+	}
+}
+
+// markDiscriminatorProperty flags the discriminator property of a model and
+// copies its language-specific name and type to the discriminator.
+func markDiscriminatorProperty(model *codegen.CodegenModel, d *codegen.CodegenDiscriminator) {
+	for _, vars := range [][]*codegen.CodegenProperty{model.Vars, model.AllVars, model.ParentVars} {
+		for _, prop := range vars {
+			if prop.BaseName != d.PropertyBaseName {
+				continue
+			}
+			prop.IsDiscriminator = true
+			if d.PropertyType == "" {
+				d.PropertyName = prop.Name
+				d.PropertyType = prop.DataType
+				d.IsEnum = prop.IsEnum
+			}
+		}
+	}
+}
+
+// descendants returns all models inheriting from a model through allOf, depth first.
+func descendants(model *codegen.CodegenModel) []*codegen.CodegenModel {
+	var result []*codegen.CodegenModel
+	visited := map[*codegen.CodegenModel]bool{model: true}
+
+	var walk func(m *codegen.CodegenModel)
+	walk = func(m *codegen.CodegenModel) {
+		for _, child := range m.Children {
+			if visited[child] {
+				continue
+			}
+			visited[child] = true
+			result = append(result, child)
+			walk(child)
+		}
+	}
+	walk(model)
+
+	return result
+}
@@ -0,0 +1,146 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xseman/openapi-generator/internal/codegen"
+)
+
+// mappingNames returns the discriminator values of a model and the models they map to.
+func mappingNames(model *codegen.CodegenModel) map[string]string {
+	mapping := make(map[string]string)
+	for _, mm := range model.Discriminator.MappedModels {
+		mapping[mm.MappingName] = mm.ModelName
+	}
+	return mapping
+}
+
+func Test_linkModels_allOfHierarchy(t *testing.T) {
+	p := loadTestSpec(t, nil, `
+openapi: 3.0.3
+info:
+	title: Pets
+	version: "1"
+paths: {}
+components:
+	schemas:
+		Pet:
+			type: object
+			required: [petType]
+			discriminator:
+				propertyName: petType
+				mapping:
+					kitty: "#/components/schemas/Cat"
+			properties:
+				petType:
+					type: string
+		Cat:
+			allOf:
+				- $ref: "#/components/schemas/Pet"
+				- type: object
+					properties:
+						meow:
+							type: boolean
+		Dog:
+			allOf:
+				- $ref: "#/components/schemas/Pet"
+		Puppy:
+			allOf:
+				- $ref: "#/components/schemas/Dog"
+`)
+	byName := modelsByName(t, p)
+	pet, dog, puppy := byName["Pet"], byName["Dog"], byName["Puppy"]
+
+	if !pet.HasChildren || len(pet.Children) != 2 || !dog.HasChildren {
+		t.Errorf("Pet children = %d, Dog has children = %v", len(pet.Children), dog.HasChildren)
+	}
+	if puppy.ParentModel != dog || dog.ParentModel != pet {
+		t.Error("parent models are not linked")
+	}
+
+	// Explicit mappings are kept, descendants at any depth are added by schema name
+	want := map[string]string{"kitty": "Cat", "Dog": "Dog", "Puppy": "Puppy"}
+	if got := mappingNames(pet); !reflect.DeepEqual(got, want) {
+		t.Errorf("mapping = %v, want %v", got, want)
+	}
+	var values []string
+	for _, mm := range pet.Discriminator.MappedModels {
+		values = append(values, mm.MappingName)
+		if mm.Model == nil {
+			t.Errorf("mapping %s has no model", mm.MappingName)
+		}
+	}
+	if !reflect.DeepEqual(values, []string{"Dog", "Puppy", "kitty"}) {
+		t.Errorf("mapping order = %v", values)
+	}
+	if !pet.HasDiscriminatorWithNonEmptyMapping || pet.Discriminator.PropertyName != "petType" || pet.Discriminator.PropertyType != "string" {
+		t.Errorf("discriminator = %+v", pet.Discriminator)
+	}
+
+	// The discriminator property is flagged on the parent and every mapped model
+	for _, model := range []*codegen.CodegenModel{pet, byName["Cat"], puppy} {
+		flagged := false
+		for _, prop := range model.AllVars {
+			flagged = flagged || (prop.BaseName == "petType" && prop.IsDiscriminator)
+		}
+		if !flagged {
+			t.Errorf("%s: petType is not flagged as discriminator", model.Name)
+		}
+	}
+}
+
+func Test_linkModels_oneOfMembers(t *testing.T) {
+	p := loadTestSpec(t, nil, `
+openapi: 3.0.3
+info:
+	title: Shapes
+	version: "1"
+paths: {}
+components:
+	schemas:
+		Shape:
+			oneOf:
+				- $ref: "#/components/schemas/Circle"
+				- $ref: "#/components/schemas/Square"
+			discriminator:
+				propertyName: kind
+				mapping:
+					sq: "#/components/schemas/Square"
+		Circle:
+			type: object
+			properties:
+				kind:
+					type: string
+		Square:
+			type: object
+			properties:
+				kind:
+					type: string
+`)
+	shape := modelsByName(t, p)["Shape"]
+	want := map[string]string{"Circle": "Circle", "sq": "Square"}
+	if got := mappingNames(shape); !reflect.DeepEqual(got, want) {
+		t.Errorf("mapping = %v, want %v", got, want)
+	}
+	if len(shape.Children) != 0 {
+		t.Errorf("oneOf members are children: %d", len(shape.Children))
+	}
+}
+
+func Test_descendants_cycle(t *testing.T) {
+	a := &codegen.CodegenModel{Name: "A"}
+	b := &codegen.CodegenModel{Name: "B"}
+	c := &codegen.CodegenModel{Name: "C"}
+	a.Children = []*codegen.CodegenModel{b}
+	b.Children = []*codegen.CodegenModel{c, a}
+	c.Children = []*codegen.CodegenModel{b}
+
+	var names []string
+	for _, model := range descendants(a) {
+		names = append(names, model.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"B", "C"}) {
+		t.Errorf("descendants = %v", names)
+	}
+}
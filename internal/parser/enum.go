@@ -0,0 +1,118 @@
+package parser
+
+import (
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/xseman/openapi-generator/internal/codegen"
+)
+
+// Vendor extensions naming and documenting enum values, by index
+var (
+	enumVarNameExtensions     = []string{"x-enum-varnames", "x-enumNames"}
+	enumDescriptionExtensions = []string{"x-enum-descriptions", "x-enumDescriptions"}
+)
+
+// enumAllowableValues returns the allowable values of an enum schema: the raw
+// "values" and the "enumVars" describing each value. An enumVar has the name
+// of its constant, its literal "value" (strings unquoted, with single quotes
+// escaped), its kind (isString, isNumeric, isBoolean or isNull), and an
+// "enumDescription" when the spec documents it.
+//
+// Names come from x-enum-varnames (or x-enumNames), otherwise from the
+// generator; names already taken by a previous value get a numeric suffix.
+func (p *Parser) enumAllowableValues(schema *openapi3.Schema) map[string]any {
+	varNames := extensionStrings(schema.Extensions, enumVarNameExtensions)
+	descriptions := extensionStrings(schema.Extensions, enumDescriptionExtensions)
+
+	taken := make(map[string]bool, len(schema.Enum))
+	enumVars := make([]map[string]any, 0, len(schema.Enum))
+	for i, v := range schema.Enum {
+		enumVar := map[string]any{
+			"value":     enumLiteral(v),
+			"isString":  false,
+			"isNumeric": false,
+			"isBoolean": false,
+			"isNull":    false,
+		}
+		switch v.(type) {
+		case nil:
+			enumVar["isNull"] = true
+		case string:
+			enumVar["isString"] = true
+		case bool:
+			enumVar["isBoolean"] = true
+		default:
+			enumVar["isNumeric"] = true
+		}
+
+		name := ""
+		if i < len(varNames) {
+			name = varNames[i]
+		}
+		if name == "" {
+			name = p.toEnumVarName(v)
+		}
+		unique := name
+		for n := 2; taken[unique]; n++ {
+			unique = name + "_" + strconv.Itoa(n)
+		}
+		taken[unique] = true
+		enumVar["name"] = unique
+
+		if i < len(descriptions) && descriptions[i] != "" {
+			enumVar["enumDescription"] = descriptions[i]
+		}
+		enumVars = append(enumVars, enumVar)
+	}
+
+	return map[string]any{
+		"values":   schema.Enum,
+		"enumVars": enumVars,
+	}
+}
+
+// hasNullEnumValue reports whether null is one of the values of an enum.
+func hasNullEnumValue(schema *openapi3.Schema) bool {
+	for _, v := range schema.Enum {
+		if v == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// enumLiteral formats an enum value for templates. Strings are not quoted, but
+// their backslashes and single quotes are escaped; numbers keep their integer or decimal form.
+func enumLiteral(value any) string {
+	if v, ok := value.(string); ok {
+		return codegen.StringLiteralEscaper.Replace(v)
+	}
+	return codegen.EnumLiteral(value)
+}
+
+// toEnumVarName returns the constant name of an enum value, delegating to the
+// generator when one is configured.
+func (p *Parser) toEnumVarName(value any) string {
+	if p.ToEnumVarNameFunc != nil {
+		return p.ToEnumVarNameFunc(value)
+	}
+	return codegen.EnumVarName(value)
+}
+
+// extensionStrings returns the string list of the first of the given vendor
+// extensions that is set. Missing or non-string items are empty.
+func extensionStrings(extensions map[string]any, names []string) []string {
+	for _, name := range names {
+		items, ok := extensions[name].([]any)
+		if !ok {
+			continue
+		}
+		values := make([]string, len(items))
+		for i, item := range items {
+			values[i], _ = item.(string)
+		}
+		return values
+	}
+	return nil
+}
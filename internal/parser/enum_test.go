@@ -0,0 +1,23 @@
+package parser
+
+import "testing"
+
+func TestEnumLiteral(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{nil, "null"},
+		{"it's", `it\'s`},
+		{`C:\temp`, `C:\\temp`},
+		{`\'`, `\\\'`},
+		{float64(3), "3"},
+		{1.5, "1.5"},
+		{true, "true"},
+	}
+	for _, tt := range tests {
+		if got := enumLiteral(tt.value); got != tt.want {
+			t.Errorf("enumLiteral(%#v) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
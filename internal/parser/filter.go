@@ -0,0 +1,261 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Filter keys
+const (
+	FilterTag             = "tag"
+	FilterPath            = "path"
+	FilterMethod          = "method"
+	FilterOperationId     = "operationId"
+	FilterVendorExtension = "vendorExtension"
+	FilterModel           = "model"
+)
+
+// FilterRule matches operations or models by one attribute.
+// Its values are alternatives and may use globs: "*" matches within a path
+// segment and "**" across segments. Vendor extension values are either a
+// name, matching when the extension is present, or name=value.
+type FilterRule struct {
+	Key    string
+	Values []string
+}
+
+// Filter selects the operations and models to generate.
+// An operation is kept when it matches every include rule and no exclude rule.
+// Models are pruned to those reachable from the kept operations, plus the
+// models included by name and everything they reference.
+type Filter struct {
+	Include []FilterRule
+	Exclude []FilterRule
+}
+
+// ParseFilter parses include and exclude rules written as "key:value1|value2",
+// e.g. "tag:orders|admin", "path:/admin/**" or "vendorExtension:x-internal=true".
+func ParseFilter(include, exclude []string) (*Filter, error) {
+	f := &Filter{}
+	for _, rule := range include {
+		parsed, err := parseFilterRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		f.Include = append(f.Include, parsed)
+	}
+	for _, rule := range exclude {
+		parsed, err := parseFilterRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		f.Exclude = append(f.Exclude, parsed)
+	}
+	return f, nil
+}
+
+func parseFilterRule(rule string) (FilterRule, error) {
+	key, values, ok := strings.Cut(rule, ":")
+	key = strings.TrimSpace(key)
+	if !ok || strings.TrimSpace(values) == "" {
+		return FilterRule{}, fmt.Errorf("invalid filter %q (expected key:value1|value2)", rule)
+	}
+
+	switch key {
+	case FilterTag, FilterPath, FilterMethod, FilterOperationId, FilterVendorExtension, FilterModel:
+	default:
+		return FilterRule{}, fmt.Errorf("invalid filter %q: unknown key %q (expected tag, path, method, operationId, vendorExtension or model)", rule, key)
+	}
+
+	parsed := FilterRule{Key: key}
+	for _, value := range strings.Split(values, "|") {
+		if value = strings.TrimSpace(value); value != "" {
+			parsed.Values = append(parsed.Values, value)
+		}
+	}
+	return parsed, nil
+}
+
+// IsEmpty reports whether the filter keeps everything.
+func (f *Filter) IsEmpty() bool {
+	return f == nil || (len(f.Include) == 0 && len(f.Exclude) == 0)
+}
+
+// filtersOperations reports whether the filter has operation rules.
+func (f *Filter) filtersOperations() bool {
+	if f == nil {
+		return false
+	}
+	for _, rule := range append(append([]FilterRule{}, f.Include...), f.Exclude...) {
+		if rule.Key != FilterModel {
+			return true
+		}
+	}
+	return false
+}
+
+// includesModels reports whether the filter has model include rules.
+func (f *Filter) includesModels() bool {
+	if f == nil {
+		return false
+	}
+	for _, rule := range f.Include {
+		if rule.Key == FilterModel {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesOperation reports whether an operation is kept.
+func (f *Filter) matchesOperation(path, method string, op *openapi3.Operation) bool {
+	if f == nil {
+		return true
+	}
+	for _, rule := range f.Include {
+		if rule.Key != FilterModel && !rule.matchesOperation(path, method, op) {
+			return false
+		}
+	}
+	for _, rule := range f.Exclude {
+		if rule.Key != FilterModel && rule.matchesOperation(path, method, op) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesOperation reports whether any value of the rule matches the operation.
+func (r FilterRule) matchesOperation(path, method string, op *openapi3.Operation) bool {
+	for _, value := range r.Values {
+		switch r.Key {
+		case FilterTag:
+			for _, tag := range op.Tags {
+				if globMatch(value, tag) {
+					return true
+				}
+			}
+		case FilterPath:
+			if globMatch(value, path) {
+				return true
+			}
+		case FilterMethod:
+			if strings.EqualFold(value, method) {
+				return true
+			}
+		case FilterOperationId:
+			if globMatch(value, op.OperationID) {
+				return true
+			}
+		case FilterVendorExtension:
+			name, expected, hasValue := strings.Cut(value, "=")
+			actual, ok := op.Extensions[name]
+			if ok && (!hasValue || fmt.Sprint(actual) == expected) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesModel reports whether any model rule of a list matches a schema name.
+func matchesModel(rules []FilterRule, name string) bool {
+	for _, rule := range rules {
+		if rule.Key != FilterModel {
+			continue
+		}
+		for _, value := range rule.Values {
+			if globMatch(value, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// filteredSchemas returns the component schemas kept by the filter,
+// or nil when all schemas are kept.
+func (p *Parser) filteredSchemas() map[string]bool {
+	f := p.Filter
+	if f.IsEmpty() || p.Doc.Components == nil {
+		return nil
+	}
+
+	used := make(map[string]bool)
+	visited := make(map[*openapi3.Schema]bool)
+
+	switch {
+	case f.filtersOperations():
+		// Schemas reachable from the kept operations
+		if p.Doc.Paths != nil {
+			for path, pathItem := range p.Doc.Paths.Map() {
+				if pathItem == nil {
+					continue
+				}
+				kept := false
+				for _, method := range httpMethods {
+					if op := pathItem.GetOperation(method); op != nil && f.matchesOperation(path, method, op) {
+						p.markSchemasInOperation(op, used, visited)
+						kept = true
+					}
+				}
+				if kept {
+					p.markSchemasInParameters(pathItem.Parameters, used, visited)
+				}
+			}
+		}
+	case f.includesModels():
+		// Only the included models
+	default:
+		for name := range p.Doc.Components.Schemas {
+			used[name] = true
+		}
+	}
+
+	// Models included by name, with everything they reference
+	for name, schemaRef := range p.Doc.Components.Schemas {
+		if matchesModel(f.Include, name) && schemaRef != nil {
+			p.markSchemaAsUsed(&openapi3.SchemaRef{Ref: "#/components/schemas/" + name}, used, visited)
+		}
+	}
+
+	for name := range used {
+		if matchesModel(f.Exclude, name) {
+			delete(used, name)
+		}
+	}
+	return used
+}
+
+// globMatch matches a value against a glob, where "*" does not cross "/" and "**" does.
+func globMatch(pattern, value string) bool {
+	if !strings.ContainsAny(pattern, "*?") {
+		return pattern == value
+	}
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch c := runes[i]; c {
+		case '*':
+			if i+1 < len(runes) && runes[i+1] == '*' {
+				sb.WriteString(".*")
+				i++
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+
+	matched, err := regexp.MatchString(sb.String(), value)
+	return err == nil && matched
+}
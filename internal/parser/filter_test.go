@@ -0,0 +1,243 @@
+package parser
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func Test_ParseFilter(t *testing.T) {
+	tests := []struct {
+		name             string
+		include, exclude []string
+		want             *Filter
+		wantErr          bool
+	}{
+		{
+			name:    "rules",
+			include: []string{"tag:orders| admin ", "path:/admin/**"},
+			exclude: []string{"vendorExtension:x-internal=true"},
+			want: &Filter{
+				Include: []FilterRule{
+					{Key: FilterTag, Values: []string{"orders", "admin"}},
+					{Key: FilterPath, Values: []string{"/admin/**"}},
+				},
+				Exclude: []FilterRule{{Key: FilterVendorExtension, Values: []string{"x-internal=true"}}},
+			},
+		},
+		{name: "none", want: &Filter{}},
+		{name: "missing values", include: []string{"tag:"}, wantErr: true},
+		{name: "missing key", include: []string{"orders"}, wantErr: true},
+		{name: "unknown key", exclude: []string{"summary:Find*"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFilter(tt.include, tt.exclude)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFilter() error = %v", err)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFilter() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_globMatch(t *testing.T) {
+	tests := []struct {
+		pattern, value string
+		want           bool
+	}{
+		{"orders", "orders", true},
+		{"orders", "order", false},
+		{"/pets/*", "/pets/{petId}", true},
+		{"/pets/*", "/pets/{petId}/photos", false},
+		{"/pets/**", "/pets/{petId}/photos", true},
+		{"/pets/?", "/pets/1", true},
+		{"/pets/?", "/pets/", false},
+		{"get*", "getPetById", true},
+		{"*Pet", "getPet", true},
+		// Regular expression characters are literal
+		{"a.b*", "a.bc", true},
+		{"a.b*", "axbc", false},
+		{"(admin)*", "(admin)Users", true},
+	}
+	for _, tt := range tests {
+		if got := globMatch(tt.pattern, tt.value); got != tt.want {
+			t.Errorf("globMatch(%q, %q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+		}
+	}
+}
+
+const filterSpec = `
+openapi: 3.0.3
+info:
+	title: Filter
+	version: "1"
+paths:
+	/orders:
+		get:
+			operationId: listOrders
+			tags: [orders]
+			responses:
+				"200":
+					description: ok
+					content:
+						application/json:
+							schema:
+								type: array
+								items:
+									$ref: "#/components/schemas/Order"
+		post:
+			operationId: createOrder
+			tags: [orders]
+			x-internal: true
+			requestBody:
+				content:
+					application/json:
+						schema:
+							$ref: "#/components/schemas/NewOrder"
+			responses:
+				"201":
+					description: created
+	/admin/users/{id}:
+		parameters:
+			- name: id
+				in: path
+				required: true
+				schema:
+					$ref: "#/components/schemas/UserId"
+		delete:
+			operationId: deleteUser
+			tags: [admin]
+			responses:
+				"204":
+					description: deleted
+components:
+	schemas:
+		Order:
+			type: object
+			properties:
+				item:
+					$ref: "#/components/schemas/Item"
+		NewOrder:
+			type: object
+			properties:
+				item:
+					$ref: "#/components/schemas/Item"
+		Item:
+			type: object
+			properties:
+				name:
+					type: string
+		UserId:
+			type: string
+		Unused:
+			type: object
+			properties:
+				owner:
+					$ref: "#/components/schemas/Item"
+`
+
+func Test_Filter_operationsAndModels(t *testing.T) {
+	tests := []struct {
+		name             string
+		include, exclude []string
+		wantOps          []string
+		wantModels       []string
+	}{
+		{
+			name:       "no filter",
+			wantOps:    []string{"createOrder", "deleteUser", "listOrders"},
+			wantModels: []string{"Item", "NewOrder", "Order", "Unused", "UserId"},
+		},
+		{
+			name:       "tag",
+			include:    []string{"tag:orders"},
+			wantOps:    []string{"createOrder", "listOrders"},
+			wantModels: []string{"Item", "NewOrder", "Order"},
+		},
+		{
+			name:       "path glob keeps path parameters",
+			include:    []string{"path:/admin/**"},
+			wantOps:    []string{"deleteUser"},
+			wantModels: []string{"UserId"},
+		},
+		{
+			name:       "rules must all match",
+			include:    []string{"tag:orders", "method:POST"},
+			wantOps:    []string{"createOrder"},
+			wantModels: []string{"Item", "NewOrder"},
+		},
+		{
+			name:       "vendor extension",
+			exclude:    []string{"vendorExtension:x-internal=true"},
+			wantOps:    []string{"deleteUser", "listOrders"},
+			wantModels: []string{"Item", "Order", "UserId"},
+		},
+		{
+			name:       "vendor extension presence",
+			include:    []string{"vendorExtension:x-internal"},
+			wantOps:    []string{"createOrder"},
+			wantModels: []string{"Item", "NewOrder"},
+		},
+		{
+			name:       "operationId glob",
+			exclude:    []string{"operationId:*Order*"},
+			wantOps:    []string{"deleteUser"},
+			wantModels: []string{"UserId"},
+		},
+		{
+			name:       "models only",
+			include:    []string{"model:Unused"},
+			wantOps:    []string{"createOrder", "deleteUser", "listOrders"},
+			wantModels: []string{"Item", "Unused"},
+		},
+		{
+			name:       "operations and included models",
+			include:    []string{"tag:admin", "model:Order"},
+			wantOps:    []string{"deleteUser"},
+			wantModels: []string{"Item", "Order", "UserId"},
+		},
+		{
+			name:       "excluded models",
+			exclude:    []string{"model:Unused|New*"},
+			wantOps:    []string{"createOrder", "deleteUser", "listOrders"},
+			wantModels: []string{"Item", "Order", "UserId"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := loadTestSpec(t, nil, filterSpec)
+			filter, err := ParseFilter(tt.include, tt.exclude)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.Filter = filter
+
+			byTag, err := p.GetOperations()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ops []string
+			for _, operations := range byTag {
+				for _, op := range operations {
+					ops = append(ops, op.OperationId)
+				}
+			}
+			sort.Strings(ops)
+			if !reflect.DeepEqual(ops, tt.wantOps) {
+				t.Errorf("operations = %v, want %v", ops, tt.wantOps)
+			}
+
+			var models []string
+			for name := range modelsByName(t, p) {
+				models = append(models, name)
+			}
+			sort.Strings(models)
+			if !reflect.DeepEqual(models, tt.wantModels) {
+				t.Errorf("models = %v, want %v", models, tt.wantModels)
+			}
+		})
+	}
+}
@@ -0,0 +1,467 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a parsed JSONPath expression (RFC 9535), as used by overlay targets.
+// It supports child names, wildcards, indices, recursive descent and filters
+// comparing a relative path with a literal, e.g.
+// "$.paths['/pets'].get", "$..parameters[?(@.in == 'query')]" or "$.tags[0]".
+type jsonPath struct {
+	segments []pathSegment
+}
+
+// pathSegment selects children of the current nodes, or of all their
+// descendants when it follows "..".
+type pathSegment struct {
+	descendant bool
+	wildcard   bool
+	names      []string
+	indices    []int
+	filter     *pathFilter
+}
+
+// pathFilter is a filter expression: alternatives (||) of conjunctions (&&) of tests.
+type pathFilter struct {
+	alternatives [][]pathTest
+}
+
+// pathTest checks a relative path for existence, or compares its value with a literal.
+type pathTest struct {
+	path     *jsonPath
+	negate   bool
+	operator string
+	literal  any
+}
+
+// jsonNode is a value selected by a path, with the container it is stored in.
+// Objects are map[string]any and arrays *[]any, so both can be modified in place.
+type jsonNode struct {
+	value  any
+	parent any
+	key    string
+	index  int
+}
+
+// parseJSONPath parses an expression starting with "$".
+func parseJSONPath(expr string) (*jsonPath, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("invalid JSONPath %q: must start with $", expr)
+	}
+	path, err := parsePathSegments(expr[1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSONPath %q: %w", expr, err)
+	}
+	return path, nil
+}
+
+func parsePathSegments(s string) (*jsonPath, error) {
+	path := &jsonPath{}
+	for len(s) > 0 {
+		var seg pathSegment
+		switch {
+		case strings.HasPrefix(s, ".."):
+			seg.descendant = true
+			s = s[2:]
+			if strings.HasPrefix(s, "[") {
+				break
+			}
+			name, rest := cutPathName(s)
+			if name == "" {
+				return nil, fmt.Errorf("missing name after ..")
+			}
+			seg.wildcard, s = name == "*", rest
+			if !seg.wildcard {
+				seg.names = []string{name}
+			}
+			path.segments = append(path.segments, seg)
+			continue
+		case strings.HasPrefix(s, "."):
+			name, rest := cutPathName(s[1:])
+			if name == "" {
+				return nil, fmt.Errorf("missing name after .")
+			}
+			seg.wildcard, s = name == "*", rest
+			if !seg.wildcard {
+				seg.names = []string{name}
+			}
+			path.segments = append(path.segments, seg)
+			continue
+		case !strings.HasPrefix(s, "["):
+			return nil, fmt.Errorf("unexpected %q", s)
+		}
+
+		end := closingBracket(s)
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed [ in %q", s)
+		}
+		if err := parseBracketSelector(strings.TrimSpace(s[1:end]), &seg); err != nil {
+			return nil, err
+		}
+		s = s[end+1:]
+		path.segments = append(path.segments, seg)
+	}
+	return path, nil
+}
+
+// cutPathName splits a dot-notation name from the rest of the expression.
+func cutPathName(s string) (name, rest string) {
+	end := strings.IndexAny(s, ".[ =!<>&|)")
+	if end < 0 {
+		return s, ""
+	}
+	return s[:end], s[end:]
+}
+
+// closingBracket returns the index of the bracket closing s[0], skipping quoted strings.
+func closingBracket(s string) int {
+	depth := 0
+	var quote rune
+	for i, c := range s {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parseBracketSelector(s string, seg *pathSegment) error {
+	switch {
+	case s == "*":
+		seg.wildcard = true
+		return nil
+	case strings.HasPrefix(s, "?"):
+		filter, err := parsePathFilter(strings.TrimSpace(s[1:]))
+		if err != nil {
+			return err
+		}
+		seg.filter = filter
+		return nil
+	}
+
+	for _, item := range splitOutsideQuotes(s, ",") {
+		item = strings.TrimSpace(item)
+		if name, ok := unquote(item); ok {
+			seg.names = append(seg.names, name)
+			continue
+		}
+		index, err := strconv.Atoi(item)
+		if err != nil {
+			return fmt.Errorf("invalid selector %q", item)
+		}
+		seg.indices = append(seg.indices, index)
+	}
+	return nil
+}
+
+func parsePathFilter(s string) (*pathFilter, error) {
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		s = s[1 : len(s)-1]
+	}
+	filter := &pathFilter{}
+	for _, alternative := range splitOutsideQuotes(s, "||") {
+		var tests []pathTest
+		for _, expr := range splitOutsideQuotes(alternative, "&&") {
+			test, err := parsePathTest(strings.TrimSpace(expr))
+			if err != nil {
+				return nil, err
+			}
+			tests = append(tests, test)
+		}
+		filter.alternatives = append(filter.alternatives, tests)
+	}
+	return filter, nil
+}
+
+func parsePathTest(s string) (pathTest, error) {
+	var test pathTest
+	if strings.HasPrefix(s, "!") {
+		test.negate = true
+		s = strings.TrimSpace(s[1:])
+	}
+	if !strings.HasPrefix(s, "@") {
+		return test, fmt.Errorf("invalid filter %q: must start with @", s)
+	}
+
+	left, right := s[1:], ""
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if i := indexOutsideQuotes(left, op); i >= 0 {
+			test.operator = op
+			left, right = strings.TrimSpace(left[:i]), strings.TrimSpace(left[i+len(op):])
+			break
+		}
+	}
+
+	path, err := parsePathSegments(strings.TrimSpace(left))
+	if err != nil {
+		return test, err
+	}
+	test.path = path
+
+	if test.operator != "" {
+		literal, err := parseLiteral(right)
+		if err != nil {
+			return test, err
+		}
+		test.literal = literal
+	}
+	return test, nil
+}
+
+func parseLiteral(s string) (any, error) {
+	if str, ok := unquote(s); ok {
+		return str, nil
+	}
+	switch s {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		return n, nil
+	}
+	return nil, fmt.Errorf("invalid literal %q", s)
+}
+
+func unquote(s string) (string, bool) {
+	if len(s) < 2 || (s[0] != '\'' && s[0] != '"') || s[len(s)-1] != s[0] {
+		return "", false
+	}
+	inner := s[1 : len(s)-1]
+	inner = strings.ReplaceAll(inner, `\`+string(s[0]), string(s[0]))
+	return strings.ReplaceAll(inner, `\\`, `\`), true
+}
+
+// splitOutsideQuotes splits s at every separator that is not quoted or bracketed.
+func splitOutsideQuotes(s, sep string) []string {
+	var parts []string
+	for {
+		i := indexOutsideQuotes(s, sep)
+		if i < 0 {
+			return append(parts, s)
+		}
+		parts = append(parts, s[:i])
+		s = s[i+len(sep):]
+	}
+}
+
+// indexOutsideQuotes returns the index of the first unquoted, unbracketed sep in s.
+func indexOutsideQuotes(s, sep string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+		case depth == 0 && strings.HasPrefix(s[i:], sep):
+			return i
+		}
+	}
+	return -1
+}
+
+// selectNodes returns the nodes of a document matched by the path.
+func (jp *jsonPath) selectNodes(root any) []jsonNode {
+	nodes := []jsonNode{{value: root}}
+	for _, seg := range jp.segments {
+		var next []jsonNode
+		for _, node := range nodes {
+			if seg.descendant {
+				for _, n := range nodeDescendants(node) {
+					next = append(next, seg.selectChildren(n)...)
+				}
+			} else {
+				next = append(next, seg.selectChildren(node)...)
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// selectChildren returns the children of a node matched by the segment.
+func (seg pathSegment) selectChildren(node jsonNode) []jsonNode {
+	var result []jsonNode
+	switch v := node.value.(type) {
+	case map[string]any:
+		if seg.wildcard || seg.filter != nil {
+			for _, child := range nodeChildren(node) {
+				if seg.filter == nil || seg.filter.matches(child.value) {
+					result = append(result, child)
+				}
+			}
+		}
+		for _, name := range seg.names {
+			if child, ok := v[name]; ok {
+				result = append(result, jsonNode{value: child, parent: v, key: name})
+			}
+		}
+	case *[]any:
+		if seg.wildcard || seg.filter != nil {
+			for _, child := range nodeChildren(node) {
+				if seg.filter == nil || seg.filter.matches(child.value) {
+					result = append(result, child)
+				}
+			}
+		}
+		for _, index := range seg.indices {
+			if index < 0 {
+				index += len(*v)
+			}
+			if index >= 0 && index < len(*v) {
+				result = append(result, jsonNode{value: (*v)[index], parent: v, index: index})
+			}
+		}
+	}
+	return result
+}
+
+// nodeChildren returns the direct children of a node, object members sorted by name.
+func nodeChildren(node jsonNode) []jsonNode {
+	var result []jsonNode
+	switch v := node.value.(type) {
+	case map[string]any:
+		for _, name := range sortedKeys(v) {
+			result = append(result, jsonNode{value: v[name], parent: v, key: name})
+		}
+	case *[]any:
+		for i, child := range *v {
+			result = append(result, jsonNode{value: child, parent: v, index: i})
+		}
+	}
+	return result
+}
+
+// nodeDescendants returns a node and all nodes below it, in document order.
+func nodeDescendants(node jsonNode) []jsonNode {
+	result := []jsonNode{node}
+	for _, child := range nodeChildren(node) {
+		result = append(result, nodeDescendants(child)...)
+	}
+	return result
+}
+
+func (f *pathFilter) matches(value any) bool {
+	for _, tests := range f.alternatives {
+		matched := true
+		for _, test := range tests {
+			if !test.matches(value) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (t pathTest) matches(value any) bool {
+	nodes := t.path.selectNodes(value)
+	var result bool
+	if t.operator == "" {
+		result = len(nodes) > 0
+	} else if len(nodes) == 1 {
+		result = compareValues(nodes[0].value, t.operator, t.literal)
+	}
+	return result != t.negate
+}
+
+func compareValues(left any, operator string, right any) bool {
+	if l, ok := toFloat(left); ok {
+		if r, ok := toFloat(right); ok {
+			switch operator {
+			case "==":
+				return l == r
+			case "!=":
+				return l != r
+			case "<":
+				return l < r
+			case "<=":
+				return l <= r
+			case ">":
+				return l > r
+			case ">=":
+				return l >= r
+			}
+		}
+	}
+	if l, ok := left.(string); ok {
+		if r, ok := right.(string); ok {
+			switch operator {
+			case "<":
+				return l < r
+			case "<=":
+				return l <= r
+			case ">":
+				return l > r
+			case ">=":
+				return l >= r
+			}
+		}
+	}
+	switch operator {
+	case "==":
+		return reflect.DeepEqual(left, right)
+	case "!=":
+		return !reflect.DeepEqual(left, right)
+	}
+	return false
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// sortedNodes orders nodes so that array elements come last-index-first,
+// keeping indices valid while elements are removed.
+func sortedNodes(nodes []jsonNode) []jsonNode {
+	sorted := append([]jsonNode(nil), nodes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].index > sorted[j].index
+	})
+	return sorted
+}
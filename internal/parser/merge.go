@@ -0,0 +1,339 @@
+package parser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// MergeStrategy decides how components and operations defined differently by
+// several input specs are merged.
+type MergeStrategy string
+
+// Merge strategies
+const (
+	MergeFail      MergeStrategy = "fail"      // Fail on any conflict
+	MergeRename    MergeStrategy = "rename"    // Prefix conflicting components with the spec name
+	MergeKeepFirst MergeStrategy = "keepFirst" // Keep the definition of the first spec
+)
+
+// ParseMergeStrategy parses a merge strategy, defaulting to MergeFail.
+func ParseMergeStrategy(value string) (MergeStrategy, error) {
+	switch MergeStrategy(value) {
+	case "", MergeFail:
+		return MergeFail, nil
+	case MergeRename, MergeKeepFirst:
+		return MergeStrategy(value), nil
+	}
+	return "", fmt.Errorf("invalid merge strategy %q (expected fail, rename or keepFirst)", value)
+}
+
+// componentKinds are the component sections merged by name.
+var componentKinds = []string{
+	"schemas", "parameters", "headers", "requestBodies", "responses",
+	"securitySchemes", "examples", "links", "callbacks",
+}
+
+// LoadSpec loads a spec from a file or an http(s) URL.
+func (p *Parser) LoadSpec(input string) error {
+	if strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "https://") {
+		if err := p.LoadFromURL(input); err != nil {
+			return fmt.Errorf("failed to load spec from URL: %w", err)
+		}
+		return nil
+	}
+	if err := p.LoadFromFile(input); err != nil {
+		return fmt.Errorf("failed to load spec from file: %w", err)
+	}
+	return nil
+}
+
+// LoadSpecs loads one or more specs and merges them into one document.
+// Each spec is loaded and validated on its own, then its paths, components
+// and tags are added to those of the previous specs. Servers and security
+// requirements that differ from the first spec are moved to the paths and
+// operations of the spec declaring them. The info of the first spec is kept.
+func (p *Parser) LoadSpecs(inputs []string) error {
+	if len(inputs) == 1 {
+		return p.LoadSpec(inputs[0])
+	}
+
+	var merged map[string]any
+	owners := make(map[string]string)
+	for _, input := range inputs {
+		if err := p.LoadSpec(input); err != nil {
+			return fmt.Errorf("%s: %w", input, err)
+		}
+		doc, err := rawDocument(p.Doc)
+		if err != nil {
+			return fmt.Errorf("%s: %w", input, err)
+		}
+
+		if merged == nil {
+			merged = doc
+			recordOwners(doc, input, owners)
+			continue
+		}
+		if err := p.mergeDocument(merged, doc, input, owners); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("failed to merge specs: %w", err)
+	}
+	doc, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return fmt.Errorf("failed to load merged spec: %w", err)
+	}
+	p.setDoc(doc, "", nil)
+	return nil
+}
+
+// rawDocument converts a loaded document, with external references
+// internalized, to plain JSON objects.
+func rawDocument(doc *openapi3.T) (map[string]any, error) {
+	doc.InternalizeRefs(context.Background(), bundleRefNameResolver())
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize spec: %w", err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to serialize spec: %w", err)
+	}
+	return raw, nil
+}
+
+// recordOwners remembers which spec defines each component and operation.
+func recordOwners(doc map[string]any, source string, owners map[string]string) {
+	components, _ := doc["components"].(map[string]any)
+	for _, kind := range componentKinds {
+		section, _ := components[kind].(map[string]any)
+		for name := range section {
+			owners[kind+"/"+name] = source
+		}
+	}
+	paths, _ := doc["paths"].(map[string]any)
+	for pathName, item := range paths {
+		item, _ := item.(map[string]any)
+		for _, method := range httpMethods {
+			if _, ok := item[strings.ToLower(method)]; ok {
+				owners[method+" "+pathName] = source
+			}
+		}
+	}
+}
+
+// mergeDocument adds the paths, components and tags of doc, loaded from source, to merged.
+func (p *Parser) mergeDocument(merged, doc map[string]any, source string, owners map[string]string) error {
+	mergedComponents := objectField(merged, "components")
+	components, _ := doc["components"].(map[string]any)
+
+	// Resolve component conflicts first, so references can be renamed
+	renames := make(map[string]string)
+	skipped := make(map[string]bool)
+	for _, kind := range componentKinds {
+		section, _ := components[kind].(map[string]any)
+		mergedSection, _ := mergedComponents[kind].(map[string]any)
+		for _, name := range sortedKeys(section) {
+			existing, ok := mergedSection[name]
+			if !ok {
+				continue
+			}
+			if reflect.DeepEqual(existing, section[name]) {
+				skipped[kind+"/"+name] = true
+				continue
+			}
+			switch p.MergeStrategy {
+			case MergeKeepFirst:
+				skipped[kind+"/"+name] = true
+			case MergeRename:
+				renamed := mergePrefix(source) + name
+				for i := 2; mergedSection[renamed] != nil || section[renamed] != nil; i++ {
+					renamed = fmt.Sprintf("%s%s%d", mergePrefix(source), name, i)
+				}
+				renames[kind+"/"+name] = renamed
+			default:
+				return fmt.Errorf("conflicting %s %q: defined differently in %s and %s", strings.TrimSuffix(kind, "s"), name, owners[kind+"/"+name], source)
+			}
+		}
+	}
+	if len(renames) > 0 {
+		renameReferences(doc, renames)
+	}
+
+	for _, kind := range componentKinds {
+		section, _ := components[kind].(map[string]any)
+		for name, value := range section {
+			if skipped[kind+"/"+name] {
+				continue
+			}
+			if renamed, ok := renames[kind+"/"+name]; ok {
+				name = renamed
+			}
+			objectField(mergedComponents, kind)[name] = value
+			owners[kind+"/"+name] = source
+		}
+	}
+
+	// Keep the servers and security of the spec on its own paths and operations
+	paths, _ := doc["paths"].(map[string]any)
+	if servers := documentServers(doc); !reflect.DeepEqual(servers, documentServers(merged)) {
+		for _, item := range paths {
+			if item, ok := item.(map[string]any); ok && item["servers"] == nil {
+				item["servers"] = servers
+			}
+		}
+	}
+	if security := documentSecurity(doc); !reflect.DeepEqual(security, documentSecurity(merged)) {
+		for _, item := range paths {
+			item, _ := item.(map[string]any)
+			for _, method := range httpMethods {
+				if op, ok := item[strings.ToLower(method)].(map[string]any); ok && op["security"] == nil {
+					op["security"] = security
+				}
+			}
+		}
+	}
+
+	mergedPaths := objectField(merged, "paths")
+	for _, pathName := range sortedKeys(paths) {
+		item, _ := paths[pathName].(map[string]any)
+		mergedItem, ok := mergedPaths[pathName].(map[string]any)
+		if !ok {
+			mergedPaths[pathName] = item
+			recordOwners(map[string]any{"paths": map[string]any{pathName: item}}, source, owners)
+			continue
+		}
+		for _, method := range httpMethods {
+			key := strings.ToLower(method)
+			op, ok := item[key]
+			if !ok {
+				continue
+			}
+			if existing, ok := mergedItem[key]; ok {
+				if reflect.DeepEqual(existing, op) || p.MergeStrategy == MergeKeepFirst {
+					continue
+				}
+				return fmt.Errorf("conflicting operation %s %s: defined differently in %s and %s", method, pathName, owners[method+" "+pathName], source)
+			}
+			mergedItem[key] = op
+			owners[method+" "+pathName] = source
+		}
+		for key, value := range item {
+			if _, ok := mergedItem[key]; !ok {
+				mergedItem[key] = value
+			}
+		}
+	}
+
+	// Tags are added when not declared yet
+	mergedTags, _ := merged["tags"].([]any)
+	declared := make(map[any]bool)
+	for _, tag := range mergedTags {
+		if tag, ok := tag.(map[string]any); ok {
+			declared[tag["name"]] = true
+		}
+	}
+	tags, _ := doc["tags"].([]any)
+	for _, tag := range tags {
+		if tag, ok := tag.(map[string]any); ok && !declared[tag["name"]] {
+			mergedTags = append(mergedTags, tag)
+			declared[tag["name"]] = true
+		}
+	}
+	if len(mergedTags) > 0 {
+		merged["tags"] = mergedTags
+	}
+	return nil
+}
+
+// renameReferences points the references and security requirements of a
+// document to renamed components, keyed by "kind/name".
+func renameReferences(value any, renames map[string]string) {
+	switch v := value.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok {
+			if kindName, ok := strings.CutPrefix(ref, "#/components/"); ok {
+				if renamed, ok := renames[kindName]; ok {
+					kind, _, _ := strings.Cut(kindName, "/")
+					v["$ref"] = "#/components/" + kind + "/" + renamed
+				}
+			}
+		}
+		if security, ok := v["security"].([]any); ok {
+			for _, requirement := range security {
+				requirement, _ := requirement.(map[string]any)
+				for name, scopes := range requirement {
+					if renamed, ok := renames["securitySchemes/"+name]; ok {
+						delete(requirement, name)
+						requirement[renamed] = scopes
+					}
+				}
+			}
+		}
+		for _, child := range v {
+			renameReferences(child, renames)
+		}
+	case []any:
+		for _, child := range v {
+			renameReferences(child, renames)
+		}
+	}
+}
+
+// documentServers returns the servers of a document, "/" when none are declared.
+func documentServers(doc map[string]any) []any {
+	if servers, ok := doc["servers"].([]any); ok && len(servers) > 0 {
+		return servers
+	}
+	return []any{map[string]any{"url": "/"}}
+}
+
+// documentSecurity returns the security requirements of a document, empty when none are declared.
+func documentSecurity(doc map[string]any) []any {
+	if security, ok := doc["security"].([]any); ok {
+		return security
+	}
+	return []any{}
+}
+
+// objectField returns the object stored under a key, creating it when missing.
+func objectField(object map[string]any, key string) map[string]any {
+	field, ok := object[key].(map[string]any)
+	if !ok {
+		field = make(map[string]any)
+		object[key] = field
+	}
+	return field
+}
+
+// mergePrefix derives the prefix of renamed components from a spec location,
+// e.g. "specs/order-service.yaml" gives "OrderService".
+func mergePrefix(source string) string {
+	base := path.Base(strings.ReplaceAll(source, "\\", "/"))
+	base = strings.TrimSuffix(base, path.Ext(base))
+
+	var sb strings.Builder
+	upper := true
+	for _, r := range base {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
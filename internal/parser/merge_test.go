@@ -0,0 +1,251 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const petSpec = `openapi: 3.0.3
+info:
+  title: Pets
+  version: "1"
+servers:
+  - url: https://pets.example.com
+tags:
+  - name: pets
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Error:
+      type: object
+      properties:
+        message:
+          type: string
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+`
+
+const orderSpec = `openapi: 3.0.3
+info:
+  title: Orders
+  version: "2"
+servers:
+  - url: https://orders.example.com
+security:
+  - apiKey: []
+tags:
+  - name: pets
+  - name: orders
+paths:
+  /orders:
+    get:
+      operationId: listOrders
+      tags: [orders]
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Order'
+components:
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+  schemas:
+    Error:
+      type: object
+      properties:
+        message:
+          type: string
+    Pet:
+      type: object
+      properties:
+        id:
+          type: integer
+    Order:
+      type: object
+      properties:
+        pet:
+          $ref: '#/components/schemas/Pet'
+`
+
+// writeSpecs writes specs to one directory and returns their paths.
+func writeSpecs(t *testing.T, specs map[string]string) map[string]string {
+	t.Helper()
+	dir := t.TempDir()
+	paths := make(map[string]string, len(specs))
+	for name, content := range specs {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		paths[name] = path
+	}
+	return paths
+}
+
+func Test_ParseMergeStrategy(t *testing.T) {
+	tests := []struct {
+		value string
+		want  MergeStrategy
+	}{
+		{"", MergeFail},
+		{"fail", MergeFail},
+		{"rename", MergeRename},
+		{"keepFirst", MergeKeepFirst},
+	}
+	for _, tt := range tests {
+		got, err := ParseMergeStrategy(tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseMergeStrategy(%q) = %q, %v; want %q", tt.value, got, err, tt.want)
+		}
+	}
+	if _, err := ParseMergeStrategy("keepLast"); err == nil {
+		t.Error("ParseMergeStrategy(keepLast) succeeded, want an error")
+	}
+}
+
+func Test_mergePrefix(t *testing.T) {
+	tests := map[string]string{
+		"specs/order-service.yaml":     "OrderService",
+		`C:\specs\pet_store.v2.json`:   "PetStoreV2",
+		"https://example.com/api.yaml": "Api",
+	}
+	for source, want := range tests {
+		if got := mergePrefix(source); got != want {
+			t.Errorf("mergePrefix(%q) = %q, want %q", source, got, want)
+		}
+	}
+}
+
+func Test_LoadSpecs_failOnConflict(t *testing.T) {
+	paths := writeSpecs(t, map[string]string{"pets.yaml": petSpec, "orders.yaml": orderSpec})
+	p := NewParser()
+	err := p.LoadSpecs([]string{paths["pets.yaml"], paths["orders.yaml"]})
+	if err == nil {
+		t.Fatal("LoadSpecs succeeded, want a conflict")
+	}
+	for _, want := range []string{`conflicting schema "Pet"`, paths["pets.yaml"], paths["orders.yaml"]} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func Test_LoadSpecs_rename(t *testing.T) {
+	paths := writeSpecs(t, map[string]string{"pets.yaml": petSpec, "order-service.yaml": orderSpec})
+	p := NewParser()
+	p.MergeStrategy = MergeRename
+	if err := p.LoadSpecs([]string{paths["pets.yaml"], paths["order-service.yaml"]}); err != nil {
+		t.Fatal(err)
+	}
+
+	schemas := p.Doc.Components.Schemas
+	var names []string
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if want := []string{"Error", "Order", "OrderServicePet", "Pet"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("schemas = %v, want %v", names, want)
+	}
+	if _, ok := schemas["Pet"].Value.Properties["name"]; !ok {
+		t.Error("Pet is not the schema of the first spec")
+	}
+	if ref := schemas["Order"].Value.Properties["pet"].Ref; ref != "#/components/schemas/OrderServicePet" {
+		t.Errorf("Order.pet references %q, want the renamed schema", ref)
+	}
+	if p.Doc.Info.Title != "Pets" {
+		t.Errorf("info title = %q, want the first spec's", p.Doc.Info.Title)
+	}
+}
+
+func Test_LoadSpecs_keepFirst(t *testing.T) {
+	changed := strings.Replace(petSpec, "operationId: listPets", "operationId: getPets", 1)
+	paths := writeSpecs(t, map[string]string{"pets.yaml": petSpec, "orders.yaml": orderSpec, "pets2.yaml": changed})
+	p := NewParser()
+	p.MergeStrategy = MergeKeepFirst
+	if err := p.LoadSpecs([]string{paths["pets.yaml"], paths["orders.yaml"], paths["pets2.yaml"]}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := p.Doc.Components.Schemas["Pet"].Value.Properties["name"]; !ok {
+		t.Error("Pet is not the schema of the first spec")
+	}
+	if ref := p.Doc.Components.Schemas["Order"].Value.Properties["pet"].Ref; ref != "#/components/schemas/Pet" {
+		t.Errorf("Order.pet references %q, want the first Pet", ref)
+	}
+	if id := p.Doc.Paths.Find("/pets").Get.OperationID; id != "listPets" {
+		t.Errorf("GET /pets is %q, want the first spec's operation", id)
+	}
+}
+
+func Test_LoadSpecs_operationConflict(t *testing.T) {
+	changed := strings.Replace(petSpec, "operationId: listPets", "operationId: getPets", 1)
+	paths := writeSpecs(t, map[string]string{"pets.yaml": petSpec, "pets2.yaml": changed})
+	for _, strategy := range []MergeStrategy{MergeFail, MergeRename} {
+		p := NewParser()
+		p.MergeStrategy = strategy
+		err := p.LoadSpecs([]string{paths["pets.yaml"], paths["pets2.yaml"]})
+		if err == nil || !strings.Contains(err.Error(), "conflicting operation GET /pets") {
+			t.Errorf("%s: error = %v, want an operation conflict", strategy, err)
+		}
+	}
+}
+
+func Test_LoadSpecs_serversSecurityAndTags(t *testing.T) {
+	orders := strings.Replace(orderSpec, "    Pet:\n      type: object\n      properties:\n        id:\n          type: integer\n", "", 1)
+	orders = strings.Replace(orders, "$ref: '#/components/schemas/Pet'", "type: string", 1)
+	paths := writeSpecs(t, map[string]string{"pets.yaml": petSpec, "orders.yaml": orders})
+	p := NewParser()
+	if err := p.LoadSpecs([]string{paths["pets.yaml"], paths["orders.yaml"]}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(p.Doc.Servers) != 1 || p.Doc.Servers[0].URL != "https://pets.example.com" {
+		t.Errorf("document servers = %v, want the first spec's", p.Doc.Servers)
+	}
+	if len(p.Doc.Security) != 0 {
+		t.Errorf("document security = %v, want none", p.Doc.Security)
+	}
+
+	pets, orderItem := p.Doc.Paths.Find("/pets"), p.Doc.Paths.Find("/orders")
+	if len(pets.Servers) != 0 || pets.Get.Security != nil {
+		t.Error("/pets got servers or security of the second spec")
+	}
+	if len(orderItem.Servers) != 1 || orderItem.Servers[0].URL != "https://orders.example.com" {
+		t.Errorf("/orders servers = %v, want the second spec's", orderItem.Servers)
+	}
+	if security := orderItem.Get.Security; security == nil || len(*security) != 1 || (*security)[0]["apiKey"] == nil {
+		t.Errorf("/orders security = %v, want apiKey", security)
+	}
+
+	var tags []string
+	for _, tag := range p.Doc.Tags {
+		tags = append(tags, tag.Name)
+	}
+	if want := []string{"pets", "orders"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("tags = %v, want %v", tags, want)
+	}
+}
@@ -0,0 +1,390 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Normalizer rules, named like the rules of the Java openapi-normalizer.
+const (
+	// Mark schemas referenced in allOf as parents, so they are inherited even without discriminator
+	RuleRefAsParentInAllOf = "REF_AS_PARENT_IN_ALLOF"
+	// Drop null members of oneOf/anyOf, making the schema nullable, and inline single remaining members
+	RuleSimplifyOneOfAnyOf = "SIMPLIFY_ONEOF_ANYOF"
+	// Replace anyOf string and enum string members by a plain string
+	RuleSimplifyAnyOfStringAndEnumString = "SIMPLIFY_ANYOF_STRING_AND_ENUM_STRING"
+	// Drop the enum of boolean schemas
+	RuleSimplifyBooleanEnum = "SIMPLIFY_BOOLEAN_ENUM"
+	// Keep only the first tag of every operation
+	RuleKeepOnlyFirstTagInOperation = "KEEP_ONLY_FIRST_TAG_IN_OPERATION"
+	// Replace the tags of every operation by the rule value
+	RuleSetTagsForAllOperations = "SET_TAGS_FOR_ALL_OPERATIONS"
+	// Replace the tags of every operation by its operationId
+	RuleSetTagsToOperationId = "SET_TAGS_TO_OPERATIONID"
+	// Drop oneOf/anyOf from schemas that declare properties
+	RuleRemoveAnyOfOneOfAndKeepPropertiesOnly = "REMOVE_ANYOF_ONEOF_AND_KEEP_PROPERTIES_ONLY"
+	// Only generate the operations matching filters such as "operationId:addPet|getPet;tag:store"
+	RuleFilter = "FILTER"
+)
+
+// normalizerRules lists the supported rules.
+var normalizerRules = map[string]bool{
+	RuleRefAsParentInAllOf:                    true,
+	RuleSimplifyOneOfAnyOf:                    true,
+	RuleSimplifyAnyOfStringAndEnumString:      true,
+	RuleSimplifyBooleanEnum:                   true,
+	RuleKeepOnlyFirstTagInOperation:           true,
+	RuleSetTagsForAllOperations:               true,
+	RuleSetTagsToOperationId:                  true,
+	RuleRemoveAnyOfOneOfAndKeepPropertiesOnly: true,
+	RuleFilter: true,
+}
+
+// ParseNormalizerRules parses "RULE=value" pairs separated by commas,
+// e.g. "SIMPLIFY_BOOLEAN_ENUM=true,SET_TAGS_FOR_ALL_OPERATIONS=api".
+func ParseNormalizerRules(values []string) (map[string]string, error) {
+	rules := make(map[string]string)
+	for _, value := range values {
+		for _, pair := range strings.Split(value, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			name, ruleValue, ok := strings.Cut(pair, "=")
+			name = strings.TrimSpace(name)
+			if !ok {
+				return nil, fmt.Errorf("invalid normalizer rule %q (expected RULE=value)", pair)
+			}
+			if !normalizerRules[name] {
+				return nil, fmt.Errorf("unknown normalizer rule %q (supported: %s)", name, strings.Join(sortedKeys(normalizerRules), ", "))
+			}
+			rules[name] = strings.TrimSpace(ruleValue)
+		}
+	}
+	return rules, nil
+}
+
+// Normalize rewrites the loaded document with the enabled rules.
+// It runs after loading and before models and operations are extracted.
+func (p *Parser) Normalize(rules map[string]string) error {
+	if p.Doc == nil || len(rules) == 0 {
+		return nil
+	}
+
+	enabled := func(rule string) bool {
+		return strings.EqualFold(rules[rule], "true")
+	}
+
+	if enabled(RuleRefAsParentInAllOf) {
+		p.walkSchemas(markAllOfRefsAsParents)
+	}
+	if enabled(RuleRemoveAnyOfOneOfAndKeepPropertiesOnly) {
+		p.walkSchemas(removeAnyOfOneOfWithProperties)
+	}
+	if enabled(RuleSimplifyAnyOfStringAndEnumString) {
+		p.walkSchemas(simplifyAnyOfStringAndEnumString)
+	}
+	if enabled(RuleSimplifyOneOfAnyOf) {
+		components := p.componentSchemaRefs()
+		p.walkSchemas(func(schemaRef *openapi3.SchemaRef) {
+			simplifyOneOfAnyOf(schemaRef, components[schemaRef])
+		})
+	}
+	if enabled(RuleSimplifyBooleanEnum) {
+		p.walkSchemas(simplifyBooleanEnum)
+	}
+
+	if enabled(RuleKeepOnlyFirstTagInOperation) {
+		p.walkOperations(func(op *openapi3.Operation) {
+			if len(op.Tags) > 1 {
+				op.Tags = op.Tags[:1]
+			}
+		})
+	}
+	if tag := rules[RuleSetTagsForAllOperations]; tag != "" {
+		p.walkOperations(func(op *openapi3.Operation) {
+			op.Tags = []string{tag}
+		})
+	}
+	if enabled(RuleSetTagsToOperationId) {
+		p.walkOperations(func(op *openapi3.Operation) {
+			if op.OperationID != "" {
+				op.Tags = []string{op.OperationID}
+			}
+		})
+	}
+
+	if filter := rules[RuleFilter]; filter != "" {
+		parsed, err := ParseFilter(strings.Split(filter, ";"), nil)
+		if err != nil {
+			return fmt.Errorf("invalid %s rule: %w", RuleFilter, err)
+		}
+		if p.Filter == nil {
+			p.Filter = &Filter{}
+		}
+		p.Filter.Include = append(p.Filter.Include, parsed.Include...)
+	}
+
+	return nil
+}
+
+// markAllOfRefsAsParents flags the schemas referenced in allOf with x-parent.
+func markAllOfRefsAsParents(schemaRef *openapi3.SchemaRef) {
+	if schemaRef.Ref != "" || schemaRef.Value == nil {
+		return
+	}
+	for _, member := range schemaRef.Value.AllOf {
+		if member == nil || member.Ref == "" || member.Value == nil {
+			continue
+		}
+		if member.Value.Extensions == nil {
+			member.Value.Extensions = make(map[string]any)
+		}
+		member.Value.Extensions["x-parent"] = true
+	}
+}
+
+// removeAnyOfOneOfWithProperties drops oneOf/anyOf from schemas declaring properties.
+func removeAnyOfOneOfWithProperties(schemaRef *openapi3.SchemaRef) {
+	schema := schemaRef.Value
+	if schemaRef.Ref != "" || schema == nil || len(schema.Properties) == 0 {
+		return
+	}
+	schema.OneOf = nil
+	schema.AnyOf = nil
+}
+
+// simplifyAnyOfStringAndEnumString replaces an anyOf of only string schemas,
+// typically a string enum plus a string to allow unknown values, by a string.
+func simplifyAnyOfStringAndEnumString(schemaRef *openapi3.SchemaRef) {
+	schema := schemaRef.Value
+	if schemaRef.Ref != "" || schema == nil || len(schema.AnyOf) < 2 {
+		return
+	}
+	for _, member := range schema.AnyOf {
+		if member == nil || member.Value == nil || !member.Value.Type.Is(openapi3.TypeString) {
+			return
+		}
+	}
+	schema.AnyOf = nil
+	schema.Type = &openapi3.Types{openapi3.TypeString}
+}
+
+// simplifyOneOfAnyOf drops the null members of oneOf/anyOf, making the schema
+// nullable instead, and replaces a schema by its only remaining member.
+// References to a component share its schema but not its SchemaRef, so a
+// component is rewritten in place and keeps a referenced member as allOf.
+func simplifyOneOfAnyOf(schemaRef *openapi3.SchemaRef, component bool) {
+	schema := schemaRef.Value
+	if schemaRef.Ref != "" || schema == nil {
+		return
+	}
+
+	for _, members := range []*openapi3.SchemaRefs{&schema.OneOf, &schema.AnyOf} {
+		if len(*members) == 0 {
+			continue
+		}
+		kept := make(openapi3.SchemaRefs, 0, len(*members))
+		for _, member := range *members {
+			if member != nil && member.Ref == "" && member.Value != nil && member.Value.Type.Is(openapi3.TypeNull) {
+				schema.Nullable = true
+				continue
+			}
+			kept = append(kept, member)
+		}
+		*members = kept
+	}
+
+	members := schema.OneOf
+	if len(members) == 0 {
+		members = schema.AnyOf
+	}
+	if len(schema.OneOf)+len(schema.AnyOf) != 1 || members[0] == nil || hasOwnDefinition(schema) {
+		return
+	}
+
+	nullable := schema.Nullable
+	only := members[0]
+	if only.Ref != "" {
+		if nullable || component {
+			// A nullable reference or a named component must stay a wrapper, keep it as allOf
+			schema.OneOf, schema.AnyOf = nil, nil
+			schema.AllOf = openapi3.SchemaRefs{only}
+			return
+		}
+		*schemaRef = *only
+		return
+	}
+	if only.Value != nil {
+		replacement := *only.Value
+		replacement.Nullable = replacement.Nullable || nullable
+		*schema = replacement
+	}
+}
+
+// componentSchemaRefs returns the SchemaRefs of the component schemas as a set.
+func (p *Parser) componentSchemaRefs() map[*openapi3.SchemaRef]bool {
+	refs := make(map[*openapi3.SchemaRef]bool)
+	if p.Doc.Components != nil {
+		for _, schemaRef := range p.Doc.Components.Schemas {
+			refs[schemaRef] = true
+		}
+	}
+	return refs
+}
+
+// hasOwnDefinition reports whether a composed schema defines more than its oneOf/anyOf,
+// in which case replacing it by a member would lose information.
+func hasOwnDefinition(schema *openapi3.Schema) bool {
+	return len(schema.Properties) > 0 || len(schema.AllOf) > 0 || schema.Discriminator != nil ||
+		(schema.Type != nil && len(schema.Type.Slice()) > 0)
+}
+
+// simplifyBooleanEnum drops the enum of boolean schemas.
+func simplifyBooleanEnum(schemaRef *openapi3.SchemaRef) {
+	schema := schemaRef.Value
+	if schemaRef.Ref != "" || schema == nil {
+		return
+	}
+	if schema.Type.Is(openapi3.TypeBoolean) && len(schema.Enum) > 0 {
+		schema.Enum = nil
+	}
+}
+
+// walkOperations calls fn for every operation of the document, in path order.
+func (p *Parser) walkOperations(fn func(op *openapi3.Operation)) {
+	if p.Doc.Paths == nil {
+		return
+	}
+	paths := make([]string, 0, p.Doc.Paths.Len())
+	for path := range p.Doc.Paths.Map() {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		pathItem := p.Doc.Paths.Value(path)
+		if pathItem == nil {
+			continue
+		}
+		for _, method := range httpMethods {
+			if op := pathItem.GetOperation(method); op != nil {
+				fn(op)
+			}
+		}
+	}
+}
+
+// walkSchemas calls fn for every schema reference of the document, before
+// visiting the schemas nested in it. Every inline schema is visited once.
+// Referenced schemas are visited through the components, so fn should only
+// rewrite the value of references without $ref.
+func (p *Parser) walkSchemas(fn func(schemaRef *openapi3.SchemaRef)) {
+	visited := make(map[*openapi3.Schema]bool)
+
+	var walk func(schemaRef *openapi3.SchemaRef)
+	walk = func(schemaRef *openapi3.SchemaRef) {
+		if schemaRef == nil {
+			return
+		}
+		fn(schemaRef)
+		schema := schemaRef.Value
+		if schemaRef.Ref != "" || schema == nil || visited[schema] {
+			return
+		}
+		visited[schema] = true
+
+		for _, name := range sortedKeys(schemaNames(schema.Properties)) {
+			walk(schema.Properties[name])
+		}
+		walk(schema.Items)
+		walk(schema.AdditionalProperties.Schema)
+		walk(schema.Not)
+		for _, members := range []openapi3.SchemaRefs{schema.AllOf, schema.OneOf, schema.AnyOf} {
+			for _, member := range members {
+				walk(member)
+			}
+		}
+	}
+
+	walkParameters := func(params openapi3.Parameters) {
+		for _, param := range params {
+			if param != nil && param.Value != nil {
+				walk(param.Value.Schema)
+				walkContent(param.Value.Content, walk)
+			}
+		}
+	}
+
+	if components := p.Doc.Components; components != nil {
+		for _, name := range sortedKeys(schemaNames(components.Schemas)) {
+			walk(components.Schemas[name])
+		}
+		for _, param := range components.Parameters {
+			if param != nil && param.Value != nil {
+				walk(param.Value.Schema)
+			}
+		}
+		for _, body := range components.RequestBodies {
+			if body != nil && body.Value != nil {
+				walkContent(body.Value.Content, walk)
+			}
+		}
+		for _, resp := range components.Responses {
+			if resp != nil && resp.Value != nil {
+				walkContent(resp.Value.Content, walk)
+			}
+		}
+		for _, header := range components.Headers {
+			if header != nil && header.Value != nil {
+				walk(header.Value.Schema)
+			}
+		}
+	}
+
+	p.walkOperations(func(op *openapi3.Operation) {
+		walkParameters(op.Parameters)
+		if op.RequestBody != nil && op.RequestBody.Value != nil {
+			walkContent(op.RequestBody.Value.Content, walk)
+		}
+		if op.Responses != nil {
+			for _, resp := range op.Responses.Map() {
+				if resp == nil || resp.Value == nil {
+					continue
+				}
+				walkContent(resp.Value.Content, walk)
+				for _, header := range resp.Value.Headers {
+					if header != nil && header.Value != nil {
+						walk(header.Value.Schema)
+					}
+				}
+			}
+		}
+	})
+	if p.Doc.Paths != nil {
+		for _, pathItem := range p.Doc.Paths.Map() {
+			if pathItem != nil {
+				walkParameters(pathItem.Parameters)
+			}
+		}
+	}
+}
+
+// walkContent calls walk for the schema of every media type.
+func walkContent(content openapi3.Content, walk func(schemaRef *openapi3.SchemaRef)) {
+	for _, mediaType := range content {
+		if mediaType != nil {
+			walk(mediaType.Schema)
+		}
+	}
+}
+
+// schemaNames returns the names of a schema map as a set.
+func schemaNames(schemas openapi3.Schemas) map[string]bool {
+	names := make(map[string]bool, len(schemas))
+	for name := range schemas {
+		names[name] = true
+	}
+	return names
+}
@@ -0,0 +1,298 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// normalizerSpec is the document every normalizer test rewrites.
+const normalizerSpec = `
+openapi: 3.0.3
+info:
+	title: Normalizer
+	version: "1"
+paths:
+	/pets:
+		get:
+			operationId: listPets
+			tags: [pets, store]
+			responses:
+				"200":
+					description: ok
+					content:
+						application/json:
+							schema:
+								$ref: "#/components/schemas/Wrapper"
+		post:
+			operationId: addPet
+			tags: [pets]
+			responses:
+				"201":
+					description: created
+components:
+	schemas:
+		Base:
+			type: object
+			properties:
+				id:
+					type: string
+		Pet:
+			allOf:
+				- $ref: "#/components/schemas/Base"
+				- type: object
+					properties:
+						name:
+							type: string
+		Wrapper:
+			oneOf:
+				- $ref: "#/components/schemas/Pet"
+		Code:
+			oneOf:
+				- type: string
+					enum: [a, b]
+		Holder:
+			type: object
+			properties:
+				code:
+					$ref: "#/components/schemas/Code"
+				inline:
+					oneOf:
+						- $ref: "#/components/schemas/Pet"
+				nullable:
+					oneOf:
+						- $ref: "#/components/schemas/Pet"
+						- type: "null"
+				choice:
+					oneOf:
+						- type: string
+						- type: integer
+						- type: "null"
+				status:
+					anyOf:
+						- type: string
+							enum: [on, off]
+						- type: string
+				flag:
+					type: boolean
+					enum: [true]
+		Mixed:
+			type: object
+			properties:
+				name:
+					type: string
+			oneOf:
+				- $ref: "#/components/schemas/Pet"
+`
+
+func TestNormalize(t *testing.T) {
+	schema := func(doc *openapi3.T, name string) *openapi3.Schema {
+		return doc.Components.Schemas[name].Value
+	}
+	property := func(doc *openapi3.T, name string) *openapi3.SchemaRef {
+		return schema(doc, "Holder").Properties[name]
+	}
+	tags := func(doc *openapi3.T, method string) []string {
+		return doc.Paths.Value("/pets").GetOperation(method).Tags
+	}
+
+	tests := []struct {
+		name  string
+		rules map[string]string
+		check func(t *testing.T, p *Parser)
+	}{
+		{
+			name:  "ref as parent in allOf",
+			rules: map[string]string{RuleRefAsParentInAllOf: "true"},
+			check: func(t *testing.T, p *Parser) {
+				if schema(p.Doc, "Base").Extensions["x-parent"] != true {
+					t.Error("Base is not marked as parent")
+				}
+			},
+		},
+		{
+			name:  "simplify oneOf inlines a single referenced member",
+			rules: map[string]string{RuleSimplifyOneOfAnyOf: "true"},
+			check: func(t *testing.T, p *Parser) {
+				if ref := property(p.Doc, "inline").Ref; ref != "#/components/schemas/Pet" {
+					t.Errorf("inline ref = %q", ref)
+				}
+			},
+		},
+		{
+			name:  "simplify oneOf keeps a nullable reference as allOf",
+			rules: map[string]string{RuleSimplifyOneOfAnyOf: "true"},
+			check: func(t *testing.T, p *Parser) {
+				nullable := property(p.Doc, "nullable")
+				if nullable.Ref != "" || !nullable.Value.Nullable || len(nullable.Value.AllOf) != 1 || len(nullable.Value.OneOf) != 0 {
+					t.Errorf("nullable = %+v", nullable.Value)
+				}
+			},
+		},
+		{
+			name:  "simplify oneOf drops null members",
+			rules: map[string]string{RuleSimplifyOneOfAnyOf: "true"},
+			check: func(t *testing.T, p *Parser) {
+				choice := property(p.Doc, "choice").Value
+				if !choice.Nullable || len(choice.OneOf) != 2 {
+					t.Errorf("choice nullable = %v with %d members", choice.Nullable, len(choice.OneOf))
+				}
+			},
+		},
+		{
+			name:  "simplify oneOf keeps components shared with their references",
+			rules: map[string]string{RuleSimplifyOneOfAnyOf: "true"},
+			check: func(t *testing.T, p *Parser) {
+				wrapper := p.Doc.Components.Schemas["Wrapper"]
+				if wrapper.Ref != "" || wrapper.Value == schema(p.Doc, "Pet") {
+					t.Fatalf("Wrapper was replaced by %q", wrapper.Ref)
+				}
+				if len(wrapper.Value.AllOf) != 1 || len(wrapper.Value.OneOf) != 0 {
+					t.Errorf("Wrapper allOf = %d, oneOf = %d", len(wrapper.Value.AllOf), len(wrapper.Value.OneOf))
+				}
+				response := p.Doc.Paths.Value("/pets").Get.Responses.Status(200).Value.Content["application/json"].Schema
+				if response.Value != wrapper.Value {
+					t.Error("the response no longer shares the Wrapper schema")
+				}
+
+				code := property(p.Doc, "code")
+				if code.Value != schema(p.Doc, "Code") || !code.Value.Type.Is(openapi3.TypeString) || len(code.Value.OneOf) != 0 {
+					t.Errorf("Holder.code = %+v, not the simplified Code schema", code.Value)
+				}
+			},
+		},
+		{
+			name:  "simplify oneOf keeps schemas with their own definition",
+			rules: map[string]string{RuleSimplifyOneOfAnyOf: "true"},
+			check: func(t *testing.T, p *Parser) {
+				if mixed := schema(p.Doc, "Mixed"); len(mixed.OneOf) != 1 || len(mixed.Properties) != 1 {
+					t.Errorf("Mixed oneOf = %d, properties = %d", len(mixed.OneOf), len(mixed.Properties))
+				}
+			},
+		},
+		{
+			name:  "simplify anyOf string and enum string",
+			rules: map[string]string{RuleSimplifyAnyOfStringAndEnumString: "true"},
+			check: func(t *testing.T, p *Parser) {
+				status := property(p.Doc, "status").Value
+				if len(status.AnyOf) != 0 || !status.Type.Is(openapi3.TypeString) {
+					t.Errorf("status anyOf = %d, type = %v", len(status.AnyOf), status.Type)
+				}
+			},
+		},
+		{
+			name:  "simplify boolean enum",
+			rules: map[string]string{RuleSimplifyBooleanEnum: "true"},
+			check: func(t *testing.T, p *Parser) {
+				if enum := property(p.Doc, "flag").Value.Enum; enum != nil {
+					t.Errorf("flag enum = %v", enum)
+				}
+			},
+		},
+		{
+			name:  "keep only first tag",
+			rules: map[string]string{RuleKeepOnlyFirstTagInOperation: "true"},
+			check: func(t *testing.T, p *Parser) {
+				if got := tags(p.Doc, "GET"); !reflect.DeepEqual(got, []string{"pets"}) {
+					t.Errorf("tags = %v", got)
+				}
+			},
+		},
+		{
+			name:  "set tags for all operations",
+			rules: map[string]string{RuleSetTagsForAllOperations: "api"},
+			check: func(t *testing.T, p *Parser) {
+				for _, method := range []string{"GET", "POST"} {
+					if got := tags(p.Doc, method); !reflect.DeepEqual(got, []string{"api"}) {
+						t.Errorf("%s tags = %v", method, got)
+					}
+				}
+			},
+		},
+		{
+			name:  "set tags to operationId",
+			rules: map[string]string{RuleSetTagsToOperationId: "true"},
+			check: func(t *testing.T, p *Parser) {
+				if got := tags(p.Doc, "POST"); !reflect.DeepEqual(got, []string{"addPet"}) {
+					t.Errorf("tags = %v", got)
+				}
+			},
+		},
+		{
+			name:  "remove anyOf/oneOf and keep properties only",
+			rules: map[string]string{RuleRemoveAnyOfOneOfAndKeepPropertiesOnly: "true"},
+			check: func(t *testing.T, p *Parser) {
+				if mixed := schema(p.Doc, "Mixed"); mixed.OneOf != nil || len(mixed.Properties) != 1 {
+					t.Errorf("Mixed oneOf = %d, properties = %d", len(mixed.OneOf), len(mixed.Properties))
+				}
+			},
+		},
+		{
+			name:  "filter",
+			rules: map[string]string{RuleFilter: "operationId:addPet"},
+			check: func(t *testing.T, p *Parser) {
+				byTag, err := p.GetOperations()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if ops := byTag["pets"]; len(ops) != 1 || ops[0].OperationId != "addPet" {
+					t.Errorf("operations = %v", byTag)
+				}
+			},
+		},
+		{
+			name:  "disabled rules",
+			rules: map[string]string{RuleSimplifyBooleanEnum: "false"},
+			check: func(t *testing.T, p *Parser) {
+				if enum := property(p.Doc, "flag").Value.Enum; len(enum) != 1 {
+					t.Errorf("flag enum = %v", enum)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Validation rejects the null members SIMPLIFY_ONEOF_ANYOF drops
+			p := NewParser()
+			p.SkipValidation = true
+			loadTestSpec(t, p, normalizerSpec)
+			if err := p.Normalize(tt.rules); err != nil {
+				t.Fatalf("Normalize: %v", err)
+			}
+			tt.check(t, p)
+		})
+	}
+}
+
+func TestParseNormalizerRules(t *testing.T) {
+	tests := []struct {
+		values  []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			values: []string{"SIMPLIFY_BOOLEAN_ENUM=true, SET_TAGS_FOR_ALL_OPERATIONS=api", "FILTER=tag:store"},
+			want: map[string]string{
+				RuleSimplifyBooleanEnum:     "true",
+				RuleSetTagsForAllOperations: "api",
+				RuleFilter:                  "tag:store",
+			},
+		},
+		{values: []string{""}, want: map[string]string{}},
+		{values: []string{"SIMPLIFY_BOOLEAN_ENUM"}, wantErr: true},
+		{values: []string{"UNKNOWN_RULE=true"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseNormalizerRules(tt.values)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseNormalizerRules(%q) error = %v", tt.values, err)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseNormalizerRules(%q) = %v, want %v", tt.values, got, tt.want)
+		}
+	}
+}
@@ -6,8 +6,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -20,6 +18,7 @@ import (
 	"github.com/xseman/openapi-generator/internal/codegen"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
 )
 
 // Parser parses OpenAPI specifications and converts them to codegen models.
@@ -28,17 +27,52 @@ type Parser struct {
 	Doc *openapi3.T
 
 	// Generator for type conversions
-	TypeMapping     map[string]string
-	GetTypeFunc     func(schemaType, format string) string
-	ToModelNameFunc func(name string) string
-	ToVarNameFunc   func(name string) string
+	TypeMapping         map[string]string
+	GetTypeFunc         func(schemaType, format string) string
+	TypeDeclarationFunc func(prop *codegen.CodegenProperty) string // Type of a property including its containers
+	IsPrimitiveFunc     func(typeName string) bool                 // Whether a language type needs no import
+	ToModelNameFunc     func(name string) string
+	ToVarNameFunc       func(name string) string
+	ToEnumVarNameFunc   func(value any) string // Constant name of an enum value
 
 	// Validation settings
 	SkipValidation bool
 
+	// API classes operations are generated into
+	TagStrategy TagStrategy
+
+	// Operations and models to generate, nil for all
+	Filter *Filter
+
+	// Overlays applied in order to the raw document before it is parsed
+	Overlays []*Overlay
+
+	// How conflicts between several input specs are resolved
+	MergeStrategy MergeStrategy
+
+	// Credentials, timeout and cache for remote specs and references
+	Remote RemoteOptions
+
+	// Record line and column numbers of the spec for located warnings
+	TrackOrigins bool
+
 	// Collected validation errors and warnings
 	ValidationErrors   []string
 	ValidationWarnings []string
+
+	// Changes made to generate the spec, such as renamed duplicate operationIds
+	Warnings []string
+
+	// Constructs generated less precisely than the spec describes them
+	Degradations []Degradation
+	silent       bool // Degradations are not recorded, for conversions that are not generated
+
+	specLocation string          // File or URL of the loaded spec, empty for merged specs
+	specOrigins  map[string]bool // Scheme and host of the remote specs, which receive the auth headers
+	swagger2     bool            // The loaded spec was converted from Swagger 2.0
+
+	positions positionIndex           // Elements of the spec and the files it references, with TrackOrigins
+	sources   map[any]*codegen.Source // Locations of the document elements, built on first use
 }
 
 // NewParser creates a new OpenAPI parser.
@@ -60,22 +94,27 @@ func (p *Parser) LoadFromFile(path string) error {
 	if err != nil {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
+	raw := data
+	data, overlaid, err := p.applyOverlays(data)
+	if err != nil {
+		return err
+	}
+	positions := p.newPositions()
+	positions.record(absPath, raw, overlaid)
 
 	// Check if it's Swagger 2.0
 	if isSwagger2(data) {
-		return p.loadSwagger2FromData(data)
+		return p.loadSwagger2FromData(data, path, positions)
 	}
 
 	// Load as OpenAPI 3.x
-	loader := openapi3.NewLoader()
-	loader.IsExternalRefsAllowed = true
-
-	doc, err := loader.LoadFromFile(absPath)
+	loader := p.newLoader(positions)
+	doc, err := loader.LoadFromDataWithPath(data, &url.URL{Path: filepath.ToSlash(absPath)})
 	if err != nil {
-		return fmt.Errorf("failed to load OpenAPI spec: %w", err)
+		return fmt.Errorf("failed to load OpenAPI spec: %w", locateLoadError(raw, path, err))
 	}
 
-	p.Doc = doc
+	p.setDoc(doc, path, positions)
 
 	// Validate the spec (unless skipped)
 	return p.validateSpec()
@@ -88,36 +127,31 @@ func (p *Parser) LoadFromURL(urlStr string) error {
 		return fmt.Errorf("failed to parse URL: %w", err)
 	}
 
-	// Fetch the URL content first to detect the version
-	client := &http.Client{}
-	resp, err := client.Get(urlStr)
+	// Fetch the URL content once, to detect the version and parse it
+	raw, err := p.fetchSpec(urlStr)
 	if err != nil {
-		return fmt.Errorf("failed to fetch URL: %w", err)
+		return err
 	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-
-	data, err := io.ReadAll(resp.Body)
+	data, overlaid, err := p.applyOverlays(raw)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+		return err
 	}
+	positions := p.newPositions()
+	positions.record(urlStr, raw, overlaid)
 
 	// Check if it's Swagger 2.0 and use proper conversion
 	if isSwagger2(data) {
-		return p.loadSwagger2FromData(data)
+		return p.loadSwagger2FromData(data, urlStr, positions)
 	}
 
 	// Load as OpenAPI 3.x
-	loader := openapi3.NewLoader()
-	loader.IsExternalRefsAllowed = true
-
-	doc, err := loader.LoadFromURI(u)
+	loader := p.newLoader(positions)
+	doc, err := loader.LoadFromDataWithPath(data, u)
 	if err != nil {
-		return fmt.Errorf("failed to load OpenAPI spec from URL: %w", err)
+		return fmt.Errorf("failed to load OpenAPI spec from URL: %w", locateLoadError(raw, urlStr, err))
 	}
 
-	p.Doc = doc
+	p.setDoc(doc, urlStr, positions)
 
 	// Validate the spec (unless skipped)
 	return p.validateSpec()
@@ -125,19 +159,27 @@ func (p *Parser) LoadFromURL(urlStr string) error {
 
 // LoadFromData loads an OpenAPI spec from raw data.
 func (p *Parser) LoadFromData(data []byte) error {
+	raw := data
+	data, overlaid, err := p.applyOverlays(data)
+	if err != nil {
+		return err
+	}
+	positions := p.newPositions()
+	positions.record("", raw, overlaid)
+
 	// Check if it's Swagger 2.0
 	if isSwagger2(data) {
-		return p.loadSwagger2FromData(data)
+		return p.loadSwagger2FromData(data, "", positions)
 	}
 
 	// Load as OpenAPI 3.x
 	loader := openapi3.NewLoader()
 	doc, err := loader.LoadFromData(data)
 	if err != nil {
-		return fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+		return fmt.Errorf("failed to parse OpenAPI spec: %w", locateLoadError(raw, "", err))
 	}
 
-	p.Doc = doc
+	p.setDoc(doc, "", positions)
 
 	// Validate the spec (unless skipped)
 	return p.validateSpec()
@@ -150,18 +192,36 @@ func isSwagger2(data []byte) bool {
 		Swagger string `json:"swagger" yaml:"swagger"`
 	}
 
-	// Try JSON first
+	// Try JSON first, then YAML
 	if err := json.Unmarshal(data, &temp); err == nil {
 		return strings.HasPrefix(temp.Swagger, "2.")
 	}
+	if err := yaml.Unmarshal(data, &temp); err == nil {
+		return strings.HasPrefix(temp.Swagger, "2.")
+	}
 
 	return false
 }
 
-// loadSwagger2FromData loads a Swagger 2.0 spec and converts it to OpenAPI 3.
-func (p *Parser) loadSwagger2FromData(data []byte) error {
+// loadSwagger2FromData loads a Swagger 2.0 spec read from a location and converts it to OpenAPI 3.
+func (p *Parser) loadSwagger2FromData(data []byte, location string, positions positionIndex) error {
 	var doc2 openapi2.T
 
+	// YAML specs are read through their JSON equivalent
+	if !json.Valid(data) {
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return fmt.Errorf("failed to parse Swagger 2.0 spec: %w", err)
+		}
+		value, err := overlayValue(&node)
+		if err != nil {
+			return fmt.Errorf("failed to parse Swagger 2.0 spec: %w", err)
+		}
+		if data, err = json.Marshal(value); err != nil {
+			return fmt.Errorf("failed to parse Swagger 2.0 spec: %w", err)
+		}
+	}
+
 	if err := json.Unmarshal(data, &doc2); err != nil {
 		return fmt.Errorf("failed to parse Swagger 2.0 spec: %w", err)
 	}
@@ -176,7 +236,8 @@ func (p *Parser) loadSwagger2FromData(data []byte) error {
 	// The openapi2conv library doesn't properly convert PathItem.Parameters to operations
 	p.fixPathItemParameters(&doc2, doc3)
 
-	p.Doc = doc3
+	p.setDoc(doc3, location, positions)
+	p.swagger2 = true
 
 	// Note: Validation with converted specs may have issues
 	// Validation will still happen if SkipValidation is false, but we accept the risk
@@ -331,13 +392,14 @@ func (p *Parser) collectWarnings() {
 
 	// Find unused schemas
 	usedSchemas := make(map[string]bool)
+	visited := make(map[*openapi3.Schema]bool)
 
 	// Mark schemas used in paths
 	if p.Doc.Paths != nil {
 		for path := range p.Doc.Paths.Map() {
 			pathItem := p.Doc.Paths.Value(path)
 			if pathItem != nil {
-				p.markSchemasInPathItem(pathItem, usedSchemas)
+				p.markSchemasInPathItem(pathItem, usedSchemas, visited)
 			}
 		}
 	}
@@ -351,42 +413,65 @@ func (p *Parser) collectWarnings() {
 }
 
 // markSchemasInPathItem marks all schemas referenced in a path item as used.
-func (p *Parser) markSchemasInPathItem(pathItem *openapi3.PathItem, usedSchemas map[string]bool) {
-	operations := []*openapi3.Operation{
-		pathItem.Get, pathItem.Post, pathItem.Put, pathItem.Delete,
-		pathItem.Patch, pathItem.Options, pathItem.Head,
+func (p *Parser) markSchemasInPathItem(pathItem *openapi3.PathItem, usedSchemas map[string]bool, visited map[*openapi3.Schema]bool) {
+	p.markSchemasInParameters(pathItem.Parameters, usedSchemas, visited)
+
+	for _, method := range httpMethods {
+		if op := pathItem.GetOperation(method); op != nil {
+			p.markSchemasInOperation(op, usedSchemas, visited)
+		}
 	}
+}
 
-	for _, op := range operations {
-		if op == nil {
-			continue
+// markSchemasInParameters marks the schemas of parameters as used.
+func (p *Parser) markSchemasInParameters(params openapi3.Parameters, usedSchemas map[string]bool, visited map[*openapi3.Schema]bool) {
+	for _, param := range params {
+		if param != nil && param.Value != nil && param.Value.Schema != nil {
+			p.markSchemaAsUsed(param.Value.Schema, usedSchemas, visited)
 		}
+	}
+}
 
-		// Mark schemas in request body
-		if op.RequestBody != nil && op.RequestBody.Value != nil {
-			for _, content := range op.RequestBody.Value.Content {
-				if content.Schema != nil {
-					p.markSchemaAsUsed(content.Schema, usedSchemas)
-				}
+// markSchemasInOperation marks all schemas referenced by an operation as used.
+func (p *Parser) markSchemasInOperation(op *openapi3.Operation, usedSchemas map[string]bool, visited map[*openapi3.Schema]bool) {
+	// Mark schemas in request body
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		for _, content := range op.RequestBody.Value.Content {
+			if content.Schema != nil {
+				p.markSchemaAsUsed(content.Schema, usedSchemas, visited)
 			}
 		}
+	}
+
+	// Mark schemas in parameters
+	p.markSchemasInParameters(op.Parameters, usedSchemas, visited)
 
-		// Mark schemas in parameters
-		for _, param := range op.Parameters {
-			if param != nil && param.Value != nil && param.Value.Schema != nil {
-				p.markSchemaAsUsed(param.Value.Schema, usedSchemas)
+	// Mark schemas in callback requests
+	for _, callbackRef := range op.Callbacks {
+		if callbackRef == nil || callbackRef.Value == nil {
+			continue
+		}
+		for _, callbackPathItem := range callbackRef.Value.Map() {
+			if callbackPathItem != nil {
+				p.markSchemasInPathItem(callbackPathItem, usedSchemas, visited)
 			}
 		}
+	}
 
-		// Mark schemas in responses
-		if op.Responses != nil {
-			for _, response := range op.Responses.Map() {
-				if response != nil && response.Value != nil {
-					for _, content := range response.Value.Content {
-						if content.Schema != nil {
-							p.markSchemaAsUsed(content.Schema, usedSchemas)
-						}
-					}
+	// Mark schemas in responses
+	if op.Responses != nil {
+		for _, response := range op.Responses.Map() {
+			if response == nil || response.Value == nil {
+				continue
+			}
+			for _, content := range response.Value.Content {
+				if content.Schema != nil {
+					p.markSchemaAsUsed(content.Schema, usedSchemas, visited)
+				}
+			}
+			for _, header := range response.Value.Headers {
+				if header != nil && header.Value != nil && header.Value.Schema != nil {
+					p.markSchemaAsUsed(header.Value.Schema, usedSchemas, visited)
 				}
 			}
 		}
@@ -394,7 +479,7 @@ func (p *Parser) markSchemasInPathItem(pathItem *openapi3.PathItem, usedSchemas
 }
 
 // markSchemaAsUsed recursively marks a schema and its references as used.
-func (p *Parser) markSchemaAsUsed(schemaRef *openapi3.SchemaRef, usedSchemas map[string]bool) {
+func (p *Parser) markSchemaAsUsed(schemaRef *openapi3.SchemaRef, usedSchemas map[string]bool, visited map[*openapi3.Schema]bool) {
 	if schemaRef == nil {
 		return
 	}
@@ -416,7 +501,7 @@ func (p *Parser) markSchemaAsUsed(schemaRef *openapi3.SchemaRef, usedSchemas map
 			// Recursively check referenced schema
 			if p.Doc.Components != nil && p.Doc.Components.Schemas != nil {
 				if refSchema := p.Doc.Components.Schemas[schemaName]; refSchema != nil && refSchema.Value != nil {
-					p.markSchemaPropertiesAsUsed(refSchema.Value, usedSchemas)
+					p.markSchemaPropertiesAsUsed(refSchema.Value, usedSchemas, visited)
 				}
 			}
 		}
@@ -425,40 +510,50 @@ func (p *Parser) markSchemaAsUsed(schemaRef *openapi3.SchemaRef, usedSchemas map
 
 	// Check the schema value itself
 	if schemaRef.Value != nil {
-		p.markSchemaPropertiesAsUsed(schemaRef.Value, usedSchemas)
+		p.markSchemaPropertiesAsUsed(schemaRef.Value, usedSchemas, visited)
 	}
 }
 
 // markSchemaPropertiesAsUsed marks schemas referenced in properties, items, etc.
-func (p *Parser) markSchemaPropertiesAsUsed(schema *openapi3.Schema, usedSchemas map[string]bool) {
-	if schema == nil {
+// Visited schemas are skipped, so cyclic inline structures terminate.
+func (p *Parser) markSchemaPropertiesAsUsed(schema *openapi3.Schema, usedSchemas map[string]bool, visited map[*openapi3.Schema]bool) {
+	if schema == nil || visited[schema] {
 		return
 	}
+	visited[schema] = true
 
 	// Check properties
 	for _, propRef := range schema.Properties {
-		p.markSchemaAsUsed(propRef, usedSchemas)
+		p.markSchemaAsUsed(propRef, usedSchemas, visited)
 	}
 
 	// Check items (for arrays)
 	if schema.Items != nil {
-		p.markSchemaAsUsed(schema.Items, usedSchemas)
+		p.markSchemaAsUsed(schema.Items, usedSchemas, visited)
 	}
 
 	// Check additionalProperties
 	if schema.AdditionalProperties.Schema != nil {
-		p.markSchemaAsUsed(schema.AdditionalProperties.Schema, usedSchemas)
+		p.markSchemaAsUsed(schema.AdditionalProperties.Schema, usedSchemas, visited)
 	}
 
 	// Check allOf, anyOf, oneOf
 	for _, s := range schema.AllOf {
-		p.markSchemaAsUsed(s, usedSchemas)
+		p.markSchemaAsUsed(s, usedSchemas, visited)
 	}
 	for _, s := range schema.AnyOf {
-		p.markSchemaAsUsed(s, usedSchemas)
+		p.markSchemaAsUsed(s, usedSchemas, visited)
 	}
 	for _, s := range schema.OneOf {
-		p.markSchemaAsUsed(s, usedSchemas)
+		p.markSchemaAsUsed(s, usedSchemas, visited)
+	}
+	p.markSchemaAsUsed(schema.Not, usedSchemas, visited)
+
+	// Check discriminator mappings, which may name schemas referenced nowhere else
+	if schema.Discriminator != nil {
+		for _, ref := range schema.Discriminator.Mapping {
+			p.markSchemaAsUsed(&openapi3.SchemaRef{Ref: ref}, usedSchemas, visited)
+		}
 	}
 }
 
@@ -489,12 +584,13 @@ func (p *Parser) GetInfo() map[string]string {
 	return info
 }
 
-// GetBasePath returns the base path from servers.
+// GetBasePath returns the URL of the first server, with server variables
+// replaced by their default values.
 func (p *Parser) GetBasePath() string {
-	if p.Doc == nil || len(p.Doc.Servers) == 0 {
+	if p.Doc == nil || len(p.Doc.Servers) == 0 || p.Doc.Servers[0] == nil {
 		return ""
 	}
-	return p.Doc.Servers[0].URL
+	return resolveServerURL(p.Doc.Servers[0])
 }
 
 // GetModels extracts all models from the OpenAPI spec.
@@ -512,16 +608,23 @@ func (p *Parser) GetModels() ([]*codegen.CodegenModel, error) {
 	}
 	sort.Strings(schemaNames)
 
+	kept := p.filteredSchemas()
 	for _, name := range schemaNames {
 		schemaRef := p.Doc.Components.Schemas[name]
 		if schemaRef == nil || schemaRef.Value == nil {
 			continue
 		}
+		if kept != nil && !kept[name] {
+			continue
+		}
 
 		model := p.schemaToModel(name, schemaRef.Value)
 		models = append(models, model)
 	}
 
+	p.linkModels(models)
+	p.markReferences(models)
+
 	return models, nil
 }
 
@@ -532,6 +635,7 @@ func (p *Parser) GetOperations() (map[string][]*codegen.CodegenOperation, error)
 	}
 
 	operationsByTag := make(map[string][]*codegen.CodegenOperation)
+	var operations []*codegen.CodegenOperation
 
 	// Get paths in sorted order
 	pathNames := make([]string, 0, p.Doc.Paths.Len())
@@ -547,33 +651,27 @@ func (p *Parser) GetOperations() (map[string][]*codegen.CodegenOperation, error)
 		}
 
 		// Process each HTTP method
-		methods := map[string]*openapi3.Operation{
-			"GET":     pathItem.Get,
-			"POST":    pathItem.Post,
-			"PUT":     pathItem.Put,
-			"DELETE":  pathItem.Delete,
-			"PATCH":   pathItem.Patch,
-			"OPTIONS": pathItem.Options,
-			"HEAD":    pathItem.Head,
-		}
-
-		for method, op := range methods {
-			if op == nil {
+		for _, method := range httpMethods {
+			op := pathItem.GetOperation(method)
+			if op == nil || !p.Filter.matchesOperation(path, method, op) {
 				continue
 			}
 
-			operation := p.operationToCodegen(path, method, op, pathItem.Parameters)
+			// Every API gets its own copy, as post-processing is done per API
+			converted := p.operationToCodegen(path, method, op, pathItem)
+			for _, tag := range p.operationTags(op) {
+				operation := cloneForTag(converted, tag)
 
-			// Determine tag
-			tag := "default"
-			if len(op.Tags) > 0 {
-				tag = op.Tags[0]
+				operationsByTag[tag] = append(operationsByTag[tag], operation)
+				operations = append(operations, operation)
 			}
-
-			operationsByTag[tag] = append(operationsByTag[tag], operation)
 		}
 	}
 
+	// Links target the operations under their final names
+	p.Warnings = append(p.Warnings, dedupOperationIds(operationsByTag)...)
+	resolveLinks(operations)
+
 	return operationsByTag, nil
 }
 
@@ -583,9 +681,16 @@ func (p *Parser) GetSecuritySchemes() ([]*codegen.CodegenSecurity, error) {
 		return nil, nil
 	}
 
-	var schemes []*codegen.CodegenSecurity
+	// Sort scheme names for deterministic output
+	names := make([]string, 0, len(p.Doc.Components.SecuritySchemes))
+	for name := range p.Doc.Components.SecuritySchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-	for name, schemeRef := range p.Doc.Components.SecuritySchemes {
+	var schemes []*codegen.CodegenSecurity
+	for _, name := range names {
+		schemeRef := p.Doc.Components.SecuritySchemes[name]
 		if schemeRef == nil || schemeRef.Value == nil {
 			continue
 		}
@@ -611,6 +716,7 @@ func (p *Parser) schemaToModel(name string, schema *openapi3.Schema) *codegen.Co
 		IsNullable:           schema.Nullable,
 		IsDeprecated:         schema.Deprecated,
 		VendorExtensions:     convertExtensions(schema.Extensions),
+		Source:               p.sourceOf(schema),
 	}
 
 	// Determine schema type
@@ -627,9 +733,8 @@ func (p *Parser) schemaToModel(name string, schema *openapi3.Schema) *codegen.Co
 	// Handle enum
 	if len(schema.Enum) > 0 {
 		model.IsEnum = true
-		model.AllowableValues = map[string]any{
-			"values": schema.Enum,
-		}
+		model.AllowableValues = p.enumAllowableValues(schema)
+		model.IsNullable = model.IsNullable || hasNullEnumValue(schema)
 	}
 
 	// Handle type-specific logic
@@ -638,15 +743,9 @@ func (p *Parser) schemaToModel(name string, schema *openapi3.Schema) *codegen.Co
 
 		switch primaryType {
 		case "object":
-			model.HasVars = len(schema.Properties) > 0
 			model.Vars = p.extractProperties(schema, model)
 			model.AllVars = model.Vars
-			model.RequiredVars = filterRequired(model.Vars)
-			model.OptionalVars = filterOptional(model.Vars)
-			model.ReadOnlyVars = filterReadOnly(model.Vars)
-			model.HasRequired = len(model.RequiredVars) > 0
-			model.HasOptional = len(model.OptionalVars) > 0
-			model.HasReadOnly = len(model.ReadOnlyVars) > 0
+			updateModelVars(model)
 
 			// Additional properties
 			if schema.AdditionalProperties.Has != nil && *schema.AdditionalProperties.Has {
@@ -659,7 +758,7 @@ func (p *Parser) schemaToModel(name string, schema *openapi3.Schema) *codegen.Co
 		case "array":
 			model.IsArray = true
 			if schema.Items != nil && schema.Items.Value != nil {
-				model.Items = p.schemaToProperty("items", schema.Items.Value, false)
+				model.Items = p.schemaRefToProperty("items", schema.Items, false)
 				model.ArrayModelType = model.Items.DataType
 			}
 
@@ -684,60 +783,22 @@ func (p *Parser) schemaToModel(name string, schema *openapi3.Schema) *codegen.Co
 
 	// Handle composition
 	if len(schema.OneOf) > 0 {
-		model.OneOf = make([]string, 0, len(schema.OneOf))
-		oneOfModelsMap := make(map[string]bool) // Use map for deduplication
-		for _, ref := range schema.OneOf {
-			if ref.Ref != "" {
-				refName := extractRefName(ref.Ref)
-				modelName := p.toModelName(refName)
-				model.OneOf = append(model.OneOf, modelName)
-				// Add non-primitive models to OneOfModels for import generation
-				if !isPrimitiveType(modelName) {
-					oneOfModelsMap[modelName] = true
-				}
-			} else if ref.Value != nil {
-				typeName := p.getTypeDeclaration(ref.Value)
-				model.OneOf = append(model.OneOf, typeName)
-				// Only add non-primitive types
-				if !isPrimitiveType(typeName) {
-					oneOfModelsMap[typeName] = true
-				}
-			}
-		}
-		// Convert map to sorted slice
-		model.OneOfModels = make([]string, 0, len(oneOfModelsMap))
-		for modelName := range oneOfModelsMap {
-			model.OneOfModels = append(model.OneOfModels, modelName)
-		}
-		sort.Strings(model.OneOfModels)
+		model.OneOf, model.OneOfModels = p.composedMembers(schema.OneOf)
 		model.HasOneOf = len(model.OneOf) > 0
 	}
 
 	if len(schema.AnyOf) > 0 {
-		model.AnyOf = make([]string, 0, len(schema.AnyOf))
-		for _, ref := range schema.AnyOf {
-			if ref.Ref != "" {
-				model.AnyOf = append(model.AnyOf, extractRefName(ref.Ref))
-			}
-		}
+		model.AnyOf, model.AnyOfModels = p.composedMembers(schema.AnyOf)
+		model.HasAnyOf = len(model.AnyOf) > 0
 	}
 
 	if len(schema.AllOf) > 0 {
-		model.AllOf = make([]string, 0, len(schema.AllOf))
-		for _, ref := range schema.AllOf {
-			if ref.Ref != "" {
-				refName := extractRefName(ref.Ref)
-				model.AllOf = append(model.AllOf, refName)
-				if model.Parent == "" {
-					// Convert to valid model name for TypeScript/other languages
-					model.Parent = p.toModelName(refName)
-				}
-			} else if ref.Value != nil && ref.Value.Properties != nil {
-				// Inline properties from allOf
-				props := p.extractProperties(ref.Value, model)
-				model.Vars = append(model.Vars, props...)
-			}
-		}
+		p.applyAllOf(model, schema)
+	}
+
+	model.ComposedSchemas = p.composedSchemas(schema)
+	if ignoresNot(model.ComposedSchemas) {
+		p.degrade(model.Source, "not constraint is ignored")
 	}
 
 	// Handle discriminator
@@ -752,8 +813,9 @@ func (p *Parser) schemaToModel(name string, schema *openapi3.Schema) *codegen.Co
 			model.Discriminator.MappedModels = make([]*codegen.MappedModel, 0)
 			for mappingName, schemaRef := range schema.Discriminator.Mapping {
 				model.Discriminator.MappedModels = append(model.Discriminator.MappedModels, &codegen.MappedModel{
-					MappingName: mappingName,
-					ModelName:   extractRefName(schemaRef),
+					MappingName:     mappingName,
+					ModelName:       p.toModelName(extractRefName(schemaRef)),
+					ExplicitMapping: true,
 				})
 			}
 		}
@@ -807,7 +869,7 @@ func (p *Parser) extractProperties(schema *openapi3.Schema, model *codegen.Codeg
 		}
 
 		required := requiredSet[name]
-		prop := p.schemaToProperty(name, propRef.Value, required)
+		prop := p.schemaRefToProperty(name, propRef, required)
 		props = append(props, prop)
 	}
 
@@ -829,6 +891,7 @@ func (p *Parser) schemaToProperty(name string, schema *openapi3.Schema, required
 		Title:                schema.Title,
 		Example:              fmt.Sprintf("%v", schema.Example),
 		VendorExtensions:     convertExtensions(schema.Extensions),
+		Source:               p.sourceOf(schema),
 	}
 
 	// Set name variants
@@ -849,24 +912,17 @@ func (p *Parser) schemaToProperty(name string, schema *openapi3.Schema, required
 	}
 	prop.OpenApiType = schemaType
 
+	prop.ComposedSchemas = p.composedSchemas(schema)
+	if ignoresNot(prop.ComposedSchemas) {
+		p.degrade(prop.Source, "not constraint is ignored")
+	}
+
 	// Handle enums
 	if len(schema.Enum) > 0 {
 		prop.IsEnum = true
 		prop.IsInnerEnum = true
-		prop.AllowableValues = map[string]any{
-			"values": schema.Enum,
-		}
-		enumVars := make([]map[string]any, 0, len(schema.Enum))
-		for _, v := range schema.Enum {
-			// Escape single quotes for TypeScript string literals
-			valueStr := fmt.Sprintf("%v", v)
-			escapedValue := strings.ReplaceAll(valueStr, "'", "\\'")
-			enumVars = append(enumVars, map[string]any{
-				"name":  toEnumVarName(valueStr),
-				"value": escapedValue,
-			})
-		}
-		prop.AllowableValues["enumVars"] = enumVars
+		prop.AllowableValues = p.enumAllowableValues(schema)
+		prop.IsNullable = prop.IsNullable || hasNullEnumValue(schema)
 		prop.EnumName = p.toModelName(name) + "Enum"
 		prop.DatatypeWithEnum = prop.EnumName
 	}
@@ -876,67 +932,39 @@ func (p *Parser) schemaToProperty(name string, schema *openapi3.Schema, required
 	case "array":
 		prop.IsArray = true
 		prop.IsContainer = true
+		prop.UniqueItems = schema.UniqueItems
 		prop.ContainerType = "array"
-		if schema.Items != nil {
-			// Check if items has a $ref
-			if schema.Items.Ref != "" {
-				refName := extractRefName(schema.Items.Ref)
-				modelName := p.toModelName(refName)
-				prop.Items = &codegen.CodegenProperty{
-					DataType: modelName,
-					Datatype: modelName,
-					IsModel:  true,
-				}
-				prop.DataType = "Array<" + modelName + ">"
-				prop.Datatype = "Array<" + modelName + ">"
-				prop.BaseType = modelName
-				prop.ComplexType = modelName
-			} else if schema.Items.Value != nil {
-				prop.Items = p.schemaToProperty(name+"Item", schema.Items.Value, false)
-				prop.DataType = "Array<" + prop.Items.DataType + ">"
-				prop.Datatype = "Array<" + prop.Items.DataType + ">"
-				prop.BaseType = prop.Items.DataType
-				if prop.Items.IsModel {
-					prop.ComplexType = prop.Items.DataType
-				}
-			} else {
-				prop.DataType = "Array<any>"
-				prop.Datatype = "Array<any>"
-				prop.BaseType = "any"
-			}
-		} else {
-			prop.DataType = "Array<any>"
-			prop.Datatype = "Array<any>"
-			prop.BaseType = "any"
+		if schema.UniqueItems {
+			prop.ContainerType = "set"
 		}
-		prop.UniqueItems = schema.UniqueItems
+		if schema.Items != nil && schema.Items.Value != nil {
+			prop.Items = p.schemaRefToProperty(name+"Item", schema.Items, false)
+		}
+		p.setContainerType(prop)
 
 	case "object":
 		if schema.Properties != nil {
 			// For inline objects with properties, we don't generate a model
-			// so treat them as "any" for simplicity
-			prop.DataType = "any"
-			prop.BaseType = "any"
+			// so treat them as any type for simplicity
+			prop.DataType = p.anyType()
+			prop.BaseType = prop.DataType
 			prop.IsPrimitiveType = true
 			prop.IsFreeFormObject = true
-		} else if schema.AdditionalProperties.Has != nil && *schema.AdditionalProperties.Has {
+			p.degradeToAny(prop.Source, schema)
+		} else if schema.AdditionalProperties.Schema != nil || (schema.AdditionalProperties.Has != nil && *schema.AdditionalProperties.Has) {
 			prop.IsMap = true
 			prop.IsContainer = true
 			prop.ContainerType = "map"
-			if schema.AdditionalProperties.Schema != nil {
-				prop.Items = p.schemaToProperty("value", schema.AdditionalProperties.Schema.Value, false)
-				prop.DataType = "{ [key: string]: " + prop.Items.DataType + "; }"
-				prop.BaseType = prop.Items.DataType
+			prop.IsPrimitiveType = true
+			if schema.AdditionalProperties.Schema != nil && schema.AdditionalProperties.Schema.Value != nil {
+				prop.Items = p.schemaRefToProperty("value", schema.AdditionalProperties.Schema, false)
 				prop.IsPrimitiveType = prop.Items.IsPrimitiveType
-			} else {
-				prop.DataType = "{ [key: string]: any; }"
-				prop.BaseType = "any"
-				prop.IsPrimitiveType = true
 			}
+			p.setContainerType(prop)
 			prop.IsFreeFormObject = true
 		} else {
 			prop.IsFreeFormObject = true
-			prop.DataType = "any"
+			prop.DataType = p.anyType()
 			prop.IsPrimitiveType = true
 		}
 
@@ -946,33 +974,24 @@ func (p *Parser) schemaToProperty(name string, schema *openapi3.Schema, required
 		switch schema.Format {
 		case "date":
 			prop.IsDate = true
-			prop.DataType = p.getSchemaType("string", "date")
 		case "date-time":
 			prop.IsDateTime = true
-			prop.DataType = p.getSchemaType("string", "date-time")
 		case "uuid":
 			prop.IsUuid = true
-			prop.DataType = "string"
 		case "uri":
 			prop.IsUri = true
-			prop.DataType = "string"
 		case "email":
 			prop.IsEmail = true
-			prop.DataType = "string"
 		case "password":
 			prop.IsPassword = true
-			prop.DataType = "string"
 		case "binary":
 			prop.IsBinary = true
 			prop.IsFile = true
-			prop.DataType = "Blob"
 			prop.IsPrimitiveType = false
 		case "byte":
 			prop.IsByteArray = true
-			prop.DataType = "string"
-		default:
-			prop.DataType = "string"
 		}
+		prop.DataType = p.getSchemaType("string", schema.Format)
 
 	case "integer":
 		prop.IsInteger = true
@@ -981,7 +1000,7 @@ func (p *Parser) schemaToProperty(name string, schema *openapi3.Schema, required
 		if schema.Format == "int64" {
 			prop.IsLong = true
 		}
-		prop.DataType = "number"
+		prop.DataType = p.getSchemaType("integer", schema.Format)
 
 	case "number":
 		prop.IsNumber = true
@@ -993,25 +1012,29 @@ func (p *Parser) schemaToProperty(name string, schema *openapi3.Schema, required
 		case "double":
 			prop.IsDouble = true
 		}
-		prop.DataType = "number"
+		prop.DataType = p.getSchemaType("number", schema.Format)
 
 	case "boolean":
 		prop.IsBoolean = true
 		prop.IsPrimitiveType = true
-		prop.DataType = "boolean"
+		prop.DataType = p.getSchemaType("boolean", schema.Format)
 
 	default:
 		// Check for $ref
 		prop.DataType = p.getSchemaType(schemaType, schema.Format)
-		if prop.DataType != "any" && prop.DataType != "" {
+		if prop.DataType != p.anyType() && prop.DataType != "" {
 			prop.IsModel = true
 		}
+		if prop.DataType == p.anyType() {
+			p.degradeToAny(prop.Source, schema)
+		}
 	}
 
 	// Handle $ref - this would need to look at the schema reference
 	if prop.DataType == "" {
-		prop.DataType = "any"
+		prop.DataType = p.anyType()
 		prop.IsAnyType = true
+		p.degrade(prop.Source, "type %q has no mapping and is generated as %s", schemaType, prop.DataType)
 	}
 
 	// Sync Datatype with DataType for template compatibility
@@ -1057,7 +1080,8 @@ func (p *Parser) schemaToProperty(name string, schema *openapi3.Schema, required
 }
 
 // operationToCodegen converts an OpenAPI operation to a CodegenOperation.
-func (p *Parser) operationToCodegen(path, method string, op *openapi3.Operation, pathParams openapi3.Parameters) *codegen.CodegenOperation {
+// The path item supplies parameters and servers shared by all of its operations.
+func (p *Parser) operationToCodegen(path, method string, op *openapi3.Operation, pathItem *openapi3.PathItem) *codegen.CodegenOperation {
 	co := &codegen.CodegenOperation{
 		Path:                path,
 		HttpMethod:          method,
@@ -1068,6 +1092,7 @@ func (p *Parser) operationToCodegen(path, method string, op *openapi3.Operation,
 		UnescapedNotes:      op.Description,
 		IsDeprecated:        op.Deprecated,
 		VendorExtensions:    convertExtensions(op.Extensions),
+		Source:              p.sourceOf(op),
 	}
 
 	// Generate operation ID if not provided
@@ -1082,14 +1107,22 @@ func (p *Parser) operationToCodegen(path, method string, op *openapi3.Operation,
 	co.Nickname = co.OperationIdCamelCase
 
 	// Set tag/baseName
+	co.Tags = p.tagsToCodegen(op.Tags)
 	if len(op.Tags) > 0 {
 		co.BaseName = op.Tags[0]
 	} else {
-		co.BaseName = "default"
+		co.BaseName = defaultTag
+	}
+
+	// Operation-level servers override path-level servers
+	if op.Servers != nil {
+		co.Servers = serversToCodegen(*op.Servers)
+	} else {
+		co.Servers = serversToCodegen(pathItem.Servers)
 	}
 
 	// Process path parameters from path item
-	for _, paramRef := range pathParams {
+	for _, paramRef := range pathItem.Parameters {
 		if paramRef == nil || paramRef.Value == nil {
 			continue
 		}
@@ -1137,28 +1170,45 @@ func (p *Parser) operationToCodegen(path, method string, op *openapi3.Operation,
 					Required:    body.Required,
 					Description: body.Description,
 					ContentType: contentType,
+					Source:      p.sourceOf(mediaType.Schema.Value),
 				}
 
 				schema := mediaType.Schema.Value
 				if mediaType.Schema.Ref != "" {
 					refName := extractRefName(mediaType.Schema.Ref)
 					modelName := p.toModelName(refName)
-					// Use "any" if model name is empty
+					// Use any type if model name is empty
 					if modelName == "" {
-						modelName = "any"
+						modelName = p.anyType()
+						p.degrade(bodyParam.Source, "reference %q has no model name and is generated as %s", mediaType.Schema.Ref, modelName)
 					}
 					bodyParam.DataType = modelName
 					bodyParam.BaseType = modelName
 					bodyParam.IsModel = true
+				} else if schema.Type.Is("array") {
+					// Arrays keep their items, for conversion of nested models
+					prop := p.schemaToProperty("body", schema, body.Required)
+					bodyParam.DataType = prop.DataType
+					bodyParam.BaseType = prop.BaseType
+					bodyParam.IsArray = prop.IsArray
+					bodyParam.IsMap = prop.IsMap
+					bodyParam.IsContainer = prop.IsContainer
+					bodyParam.UniqueItems = prop.UniqueItems
+					bodyParam.IsPrimitiveType = prop.IsPrimitiveType
+					bodyParam.Items = prop.Items
+					bodyParam.MostInnerItems = prop.MostInnerItems
 				} else {
 					bodyParam.DataType = p.getTypeDeclaration(schema)
 					bodyParam.BaseType = bodyParam.DataType
+					if bodyParam.DataType == p.anyType() {
+						p.degradeToAny(bodyParam.Source, schema)
+					}
 				}
 
-				// Use "any" if type declaration is empty
+				// Use any type if type declaration is empty
 				if bodyParam.DataType == "" {
-					bodyParam.DataType = "any"
-					bodyParam.BaseType = "any"
+					bodyParam.DataType = p.anyType()
+					bodyParam.BaseType = bodyParam.DataType
 				}
 
 				co.BodyParam = bodyParam
@@ -1192,6 +1242,9 @@ func (p *Parser) operationToCodegen(path, method string, op *openapi3.Operation,
 
 			resp := p.responseToCodegen(code, respRef.Value)
 			co.Responses = append(co.Responses, resp)
+			if resp.HasLinks {
+				co.HasLinks = true
+			}
 
 			// Set return type from 2xx response
 			if strings.HasPrefix(code, "2") && resp.DataType != "" {
@@ -1199,9 +1252,11 @@ func (p *Parser) operationToCodegen(path, method string, op *openapi3.Operation,
 				co.ReturnBaseType = resp.BaseType
 				co.ReturnSimpleType = resp.SimpleType
 				co.ReturnTypeIsPrimitive = resp.PrimitiveType
+				co.ReturnProperty = resp.ReturnProperty
 				if resp.IsArray {
 					co.IsArray = true
 					co.ReturnContainer = "array"
+					co.UniqueItems = resp.UniqueItems
 				}
 				if resp.IsMap {
 					co.IsMap = true
@@ -1224,21 +1279,11 @@ func (p *Parser) operationToCodegen(path, method string, op *openapi3.Operation,
 	}
 
 	// Process security
-	if op.Security != nil {
-		for _, secReq := range *op.Security {
-			for name, scopes := range secReq {
-				sec := &codegen.CodegenSecurity{
-					Name:   name,
-					Scopes: make([]map[string]any, len(scopes)),
-				}
-				for i, scope := range scopes {
-					sec.Scopes[i] = map[string]any{"scope": scope}
-				}
-				co.AuthMethods = append(co.AuthMethods, sec)
-			}
-		}
-		co.HasAuthMethods = len(co.AuthMethods) > 0
-	}
+	p.applySecurity(co, op)
+
+	// Process callbacks
+	co.Callbacks = p.callbacksToCodegen(co, op.Callbacks)
+	co.HasCallbacks = len(co.Callbacks) > 0
 
 	// Deduplicate parameters by name (path params can override operation params)
 	co.AllParams = deduplicateParams(co.AllParams)
@@ -1277,6 +1322,7 @@ func (p *Parser) parameterToCodegen(param *openapi3.Parameter) *codegen.CodegenP
 		Style:                param.Style,
 		IsExplode:            param.Explode != nil && *param.Explode,
 		VendorExtensions:     convertExtensions(param.Extensions),
+		Source:               p.sourceOf(param),
 	}
 
 	// Set name variants
@@ -1300,13 +1346,14 @@ func (p *Parser) parameterToCodegen(param *openapi3.Parameter) *codegen.CodegenP
 	// Process schema
 	if param.Schema != nil && param.Schema.Value != nil {
 		schema := param.Schema.Value
-		prop := p.schemaToProperty(param.Name, schema, param.Required)
+		prop := p.schemaRefToProperty(param.Name, param.Schema, param.Required)
 
 		cp.DataType = prop.DataType
 		cp.BaseType = prop.BaseType
 		cp.DataFormat = schema.Format
 		cp.IsArray = prop.IsArray
 		cp.IsMap = prop.IsMap
+		cp.UniqueItems = prop.UniqueItems
 		cp.IsString = prop.IsString
 		cp.IsInteger = prop.IsInteger
 		cp.IsLong = prop.IsLong
@@ -1317,6 +1364,7 @@ func (p *Parser) parameterToCodegen(param *openapi3.Parameter) *codegen.CodegenP
 		cp.IsDate = prop.IsDate
 		cp.IsDateTime = prop.IsDateTime
 		cp.IsEnum = prop.IsEnum
+		cp.IsEnumRef = prop.IsEnumRef
 		cp.IsPrimitiveType = prop.IsPrimitiveType
 		cp.IsModel = prop.IsModel
 		cp.IsContainer = prop.IsContainer
@@ -1332,12 +1380,15 @@ func (p *Parser) parameterToCodegen(param *openapi3.Parameter) *codegen.CodegenP
 		}
 	}
 
-	// Ensure DataType is never empty - default to "any" if not set
+	// Ensure DataType is never empty - default to any type if not set
 	if cp.DataType == "" {
-		cp.DataType = "any"
-		cp.BaseType = "any"
+		cp.DataType = p.anyType()
+		cp.BaseType = cp.DataType
 		cp.IsPrimitiveType = true
 		cp.IsAnyType = true
+		if len(param.Content) > 0 {
+			p.degrade(cp.Source, "parameter content is generated as %s", cp.DataType)
+		}
 	}
 
 	// Ensure DatatypeWithEnum is set
@@ -1389,7 +1440,8 @@ func (p *Parser) responseToCodegen(code string, resp *openapi3.Response) *codege
 			refName := extractRefName(mediaType.Schema.Ref)
 			modelName := p.toModelName(refName)
 			if modelName == "" {
-				modelName = "any"
+				modelName = p.anyType()
+				p.degrade(p.sourceOf(mediaType.Schema.Value), "reference %q has no model name and is generated as %s", mediaType.Schema.Ref, modelName)
 			}
 			cr.DataType = modelName
 			cr.BaseType = modelName
@@ -1412,9 +1464,16 @@ func (p *Parser) responseToCodegen(code string, resp *openapi3.Response) *codege
 			cr.IsBoolean = prop.IsBoolean
 			cr.Items = prop.Items
 			cr.ContainerType = prop.ContainerType
+			cr.UniqueItems = prop.UniqueItems
 
 			cr.SimpleType = prop.IsPrimitiveType
 			cr.PrimitiveType = prop.IsPrimitiveType
+			cr.ReturnProperty = prop
+
+			// Containers of primitive values need no conversion
+			if prop.IsContainer && (prop.MostInnerItems == nil || !prop.MostInnerItems.IsModel) {
+				cr.PrimitiveType = true
+			}
 		}
 
 		break // Use first content type
@@ -1432,17 +1491,25 @@ func (p *Parser) responseToCodegen(code string, resp *openapi3.Response) *codege
 			Description: header.Description,
 		}
 		if header.Schema != nil && header.Schema.Value != nil {
+			prop.Source = p.sourceOf(header.Schema.Value)
 			prop.DataType = p.getTypeDeclaration(header.Schema.Value)
+			if prop.DataType == p.anyType() {
+				p.degradeToAny(prop.Source, header.Schema.Value)
+			}
 		}
 		// Ensure DataType is never empty
 		if prop.DataType == "" {
-			prop.DataType = "any"
+			prop.DataType = p.anyType()
 		}
 		prop.Datatype = prop.DataType
 		cr.Headers = append(cr.Headers, prop)
 	}
 	cr.HasHeaders = len(cr.Headers) > 0
 
+	// Process links
+	cr.Links = p.linksToCodegen(resp.Links)
+	cr.HasLinks = len(cr.Links) > 0
+
 	return cr
 }
 
@@ -1514,41 +1581,30 @@ func (p *Parser) securitySchemeToCodegen(name string, scheme *openapi3.SecurityS
 
 // Helper functions
 
+// getSchemaType returns the language type of an OpenAPI type and format.
+// Type names are decided by the generator; without one the OpenAPI names are
+// kept, with the format taking precedence (e.g. "date-time", "int64").
 func (p *Parser) getSchemaType(schemaType, format string) string {
+	if schemaType == "" {
+		schemaType = "AnyType"
+	}
 	if p.GetTypeFunc != nil {
 		return p.GetTypeFunc(schemaType, format)
 	}
-
-	// Default TypeScript mappings
-	switch schemaType {
-	case "integer", "number":
-		return "number"
-	case "boolean":
-		return "boolean"
-	case "string":
-		switch format {
-		case "date", "date-time":
-			return "Date"
-		case "binary":
-			return "Blob"
-		default:
-			return "string"
-		}
-	case "array":
-		return "Array"
-	case "object":
-		return "any"
-	default:
-		if schemaType == "" {
-			return "any"
-		}
-		return schemaType
+	if format != "" {
+		return format
 	}
+	return schemaType
+}
+
+// anyType returns the language type used when a schema has no usable type.
+func (p *Parser) anyType() string {
+	return p.getSchemaType("AnyType", "")
 }
 
 func (p *Parser) getTypeDeclaration(schema *openapi3.Schema) string {
 	if schema == nil {
-		return "any"
+		return p.anyType()
 	}
 
 	// Handle allOf - return the first ref if all are refs
@@ -1595,32 +1651,25 @@ func (p *Parser) collectImports(model *codegen.CodegenModel) []string {
 	imports := make(map[string]bool)
 
 	for _, prop := range model.Vars {
-		// Skip self-references to avoid circular imports
-		if prop.IsModel && prop.DataType != model.Classname && !isPrimitiveType(prop.DataType) {
-			imports[prop.DataType] = true
-		}
-		// Also check items for arrays
-		if prop.Items != nil && prop.Items.IsModel && prop.Items.DataType != model.Classname && !isPrimitiveType(prop.Items.DataType) {
-			imports[prop.Items.DataType] = true
-		}
+		p.addPropertyImports(imports, prop)
 	}
+	// Skip self-references to avoid circular imports
+	delete(imports, model.Classname)
 
 	for _, ref := range model.OneOf {
 		// Skip self-references to avoid circular imports
-		if ref != model.Classname && !isPrimitiveType(ref) {
+		if ref != model.Classname && !p.isPrimitiveType(ref) {
 			imports[ref] = true
 		}
 	}
 	for _, ref := range model.AnyOf {
 		// Skip self-references to avoid circular imports
-		if ref != model.Classname && !isPrimitiveType(ref) {
+		if ref != model.Classname && !p.isPrimitiveType(ref) {
 			imports[ref] = true
 		}
 	}
-	for _, ref := range model.AllOf {
-		if ref != model.Classname && !isPrimitiveType(ref) {
-			imports[ref] = true
-		}
+	if model.Parent != "" && model.Parent != model.Classname {
+		imports[model.Parent] = true
 	}
 
 	result := make([]string, 0, len(imports))
@@ -1631,24 +1680,43 @@ func (p *Parser) collectImports(model *codegen.CodegenModel) []string {
 	return result
 }
 
+// addPropertyImports adds the models a property references to imports, down to
+// the innermost items of nested containers.
+func (p *Parser) addPropertyImports(imports map[string]bool, prop *codegen.CodegenProperty) {
+	for ; prop != nil; prop = prop.Items {
+		if prop.IsModel && !p.isPrimitiveType(prop.DataType) {
+			imports[prop.DataType] = true
+		}
+	}
+}
+
 func (p *Parser) collectOperationImports(op *codegen.CodegenOperation) []string {
 	imports := make(map[string]bool)
 
 	// From parameters
 	for _, param := range op.AllParams {
-		if param.IsModel && !isPrimitiveType(param.DataType) {
+		if param.IsModel && !p.isPrimitiveType(param.DataType) {
 			imports[param.DataType] = true
 		}
-		if param.Items != nil && param.Items.IsModel && !isPrimitiveType(param.Items.DataType) {
-			imports[param.Items.DataType] = true
+		if param.Items != nil {
+			p.addPropertyImports(imports, param.Items)
 		}
 	}
 
 	// From return type - also check that ReturnBaseType is not primitive
-	if op.ReturnType != "" && !isPrimitiveType(op.ReturnType) && !isPrimitiveType(op.ReturnBaseType) {
+	if op.ReturnType != "" && !p.isPrimitiveType(op.ReturnType) && !p.isPrimitiveType(op.ReturnBaseType) {
 		imports[op.ReturnBaseType] = true
 	}
 
+	// From callback requests, so handler payload types can be referenced
+	for _, cb := range op.Callbacks {
+		for _, request := range cb.Operations {
+			for _, imp := range request.Imports {
+				imports[imp] = true
+			}
+		}
+	}
+
 	result := make([]string, 0, len(imports))
 	for imp := range imports {
 		result = append(result, imp)
@@ -1834,47 +1902,35 @@ func sanitizeTag(path string) string {
 	return toPascalCase(path)
 }
 
-func toEnumVarName(value string) string {
-	// Convert enum value to a valid enum member name
-	name := strings.ToUpper(value)
-	name = strings.ReplaceAll(name, " ", "_")
-	name = strings.ReplaceAll(name, "-", "_")
-	name = strings.ReplaceAll(name, ".", "_")
-	name = strings.ReplaceAll(name, "+", "PLUS")
-
-	// Handle names starting with a digit - prefix with underscore
-	if len(name) > 0 && name[0] >= '0' && name[0] <= '9' {
-		name = "_" + name
-	}
-
-	// Remove any remaining invalid characters
-	validName := ""
-	for i, r := range name {
-		if (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || r == '_' || (i > 0 && r >= '0' && r <= '9') {
-			validName += string(r)
-		}
+// isPrimitiveType reports whether a language type is built in, so it needs no import.
+func (p *Parser) isPrimitiveType(t string) bool {
+	if p.IsPrimitiveFunc != nil {
+		return p.IsPrimitiveFunc(t)
 	}
-	if validName == "" {
-		validName = "VALUE"
-	}
-	return validName
+	return t == p.anyType() || neutralPrimitives[t]
 }
 
-func isPrimitiveType(t string) bool {
-	primitives := map[string]bool{
-		"string": true, "number": true, "boolean": true,
-		"any": true, "void": true, "null": true,
-		"Date": true, "Blob": true,
-	}
-	return primitives[t]
+// neutralPrimitives are the type names produced by getSchemaType without a generator.
+var neutralPrimitives = map[string]bool{
+	"string": true, "integer": true, "number": true, "boolean": true,
+	"object": true, "null": true, "void": true,
+	"int32": true, "int64": true, "float": true, "double": true,
+	"date": true, "date-time": true, "binary": true, "byte": true,
+	"uuid": true, "uri": true, "email": true, "password": true,
 }
 
 func scopesToList(scopes map[string]string) []map[string]any {
+	names := make([]string, 0, len(scopes))
+	for scope := range scopes {
+		names = append(names, scope)
+	}
+	sort.Strings(names)
+
 	result := make([]map[string]any, 0, len(scopes))
-	for scope, desc := range scopes {
+	for _, scope := range names {
 		result = append(result, map[string]any{
 			"scope":       scope,
-			"description": desc,
+			"description": scopes[scope],
 		})
 	}
 	return result
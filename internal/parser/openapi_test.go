@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xseman/openapi-generator/internal/codegen"
+)
+
+// loadTestSpec returns a parser loaded with an inline spec. The spec is
+// indented with tabs for readability; they are replaced by spaces.
+func loadTestSpec(t *testing.T, p *Parser, spec string) *Parser {
+	t.Helper()
+	if p == nil {
+		p = NewParser()
+	}
+	if err := p.LoadFromData([]byte(strings.ReplaceAll(spec, "\t", "  "))); err != nil {
+		t.Fatalf("LoadFromData: %v", err)
+	}
+	return p
+}
+
+// goTypes maps OpenAPI types to Go-like names, so no TypeScript name can leak
+// out of the parser unnoticed.
+var goTypes = map[string]string{
+	"AnyType": "interface{}", "string": "string", "integer": "int", "int64": "int64",
+	"number": "float64", "boolean": "bool", "object": "interface{}", "date-time": "time.Time",
+}
+
+func newGoTypeParser() *Parser {
+	p := NewParser()
+	p.GetTypeFunc = func(schemaType, format string) string {
+		if t, ok := goTypes[format]; ok {
+			return t
+		}
+		if t, ok := goTypes[schemaType]; ok {
+			return t
+		}
+		return schemaType
+	}
+	var declaration func(prop *codegen.CodegenProperty) string
+	declaration = func(prop *codegen.CodegenProperty) string {
+		inner := "interface{}"
+		if prop.Items != nil {
+			inner = declaration(prop.Items)
+		}
+		switch {
+		case prop.IsMap:
+			return "map[string]" + inner
+		case prop.IsArray:
+			return "[]" + inner
+		}
+		return prop.DataType
+	}
+	p.TypeDeclarationFunc = declaration
+	p.IsPrimitiveFunc = func(typeName string) bool {
+		for _, t := range goTypes {
+			if t == typeName {
+				return true
+			}
+		}
+		return false
+	}
+	return p
+}
+
+func TestParserWithGoTypes(t *testing.T) {
+	p := loadTestSpec(t, newGoTypeParser(), `
+openapi: 3.0.3
+info: {title: T, version: 1.0.0}
+paths:
+	/pets:
+		get:
+			operationId: listPets
+			parameters:
+				- {name: ids, in: query, schema: {type: array, items: {type: integer, format: int64}}}
+			responses:
+				'200':
+					description: ok
+					content:
+						application/json:
+							schema: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+components:
+	schemas:
+		Pet:
+			type: object
+			required: [name]
+			properties:
+				name: {type: string}
+				born: {type: string, format: date-time}
+				extra: {}
+				meta: {type: object, properties: {a: {type: string}}}
+				grid:
+					type: array
+					items: {type: array, items: {type: object, additionalProperties: {$ref: '#/components/schemas/Tag'}}}
+				choice:
+					oneOf: [{type: string}, {type: integer}]
+		Tag:
+			type: object
+			properties:
+				label: {type: string}
+		Id:
+			oneOf: [{type: string}, {$ref: '#/components/schemas/Tag'}]
+`)
+	models, err := p.GetModels()
+	if err != nil {
+		t.Fatalf("GetModels: %v", err)
+	}
+	byName := make(map[string]*codegen.CodegenModel)
+	for _, model := range models {
+		byName[model.Classname] = model
+	}
+
+	pet := byName["Pet"]
+	if pet == nil {
+		t.Fatalf("no Pet model in %v", models)
+	}
+	wantTypes := map[string]string{
+		"name":   "string",
+		"born":   "time.Time",
+		"extra":  "interface{}",
+		"meta":   "interface{}",
+		"grid":   "[][]map[string]Tag",
+		"choice": "interface{}",
+	}
+	for _, prop := range pet.Vars {
+		if want, ok := wantTypes[prop.BaseName]; ok && prop.DataType != want {
+			t.Errorf("Pet.%s DataType = %q, want %q", prop.BaseName, prop.DataType, want)
+		}
+	}
+	if want := []string{"Tag"}; !reflect.DeepEqual(pet.Imports, want) {
+		t.Errorf("Pet imports = %v, want %v", pet.Imports, want)
+	}
+	if id := byName["Id"]; id == nil || !reflect.DeepEqual(id.OneOf, []string{"string", "Tag"}) {
+		t.Errorf("Id oneOf = %v, want [string Tag]", id)
+	}
+
+	operations, err := p.GetOperations()
+	if err != nil {
+		t.Fatalf("GetOperations: %v", err)
+	}
+	var types []string
+	for _, ops := range operations {
+		for _, op := range ops {
+			types = append(types, op.ReturnType, op.ReturnBaseType)
+			types = append(types, op.Imports...)
+			for _, param := range op.AllParams {
+				types = append(types, param.DataType)
+			}
+		}
+	}
+	if want := []string{"[]Pet", "Pet", "Pet", "[]int64"}; !reflect.DeepEqual(types, want) {
+		t.Errorf("operation types = %v, want %v", types, want)
+	}
+
+	// No TypeScript type name may come from the parser itself
+	for _, model := range models {
+		for _, prop := range model.AllVars {
+			for _, name := range []string{prop.DataType, prop.BaseType, prop.ComplexType} {
+				for _, ts := range []string{"any", "Array<", "Set<", "[key: string]", "Date", "Blob"} {
+					if strings.Contains(name, ts) {
+						t.Errorf("%s.%s has TypeScript type %q", model.Classname, prop.BaseName, name)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v3"
+)
+
+// Overlay is an OpenAPI Overlay 1.0 document, patching a spec without forking it.
+// See https://spec.openapis.org/overlay/v1.0.0.html.
+type Overlay struct {
+	Overlay string          `yaml:"overlay"`
+	Info    OverlayInfo     `yaml:"info"`
+	Extends string          `yaml:"extends"`
+	Actions []OverlayAction `yaml:"actions"`
+}
+
+// OverlayInfo describes an overlay.
+type OverlayInfo struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+// OverlayAction updates or removes the nodes selected by a JSONPath target.
+// An update is merged into objects, appended to arrays and replaces other values.
+type OverlayAction struct {
+	Target      string    `yaml:"target"`
+	Description string    `yaml:"description"`
+	Update      yaml.Node `yaml:"update"`
+	Remove      bool      `yaml:"remove"`
+
+	path *jsonPath
+}
+
+// LoadOverlay loads and checks an overlay from a JSON or YAML file.
+func LoadOverlay(path string) (*Overlay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read overlay: %w", err)
+	}
+
+	var overlay Overlay
+	if err := yaml.Unmarshal(data, &overlay); err != nil {
+		return nil, fmt.Errorf("failed to parse overlay %s: %w", path, err)
+	}
+	if !strings.HasPrefix(overlay.Overlay, "1.") {
+		return nil, fmt.Errorf("unsupported overlay %s: version %q (expected 1.x)", path, overlay.Overlay)
+	}
+	for i := range overlay.Actions {
+		action := &overlay.Actions[i]
+		target, err := parseJSONPath(action.Target)
+		if err != nil {
+			return nil, fmt.Errorf("overlay %s, action %d: %w", path, i+1, err)
+		}
+		action.path = target
+	}
+	return &overlay, nil
+}
+
+// applyOverlays applies the parser overlays in order to a raw JSON or YAML
+// document and returns the patched document as JSON, with the positions of its
+// elements in the raw document. Without overlays the document is returned
+// unchanged, and the positions are nil.
+func (p *Parser) applyOverlays(data []byte) ([]byte, map[string]openapi3.Location, error) {
+	if len(p.Overlays) == 0 {
+		return data, nil, nil
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse spec for overlays: %w", err)
+	}
+	doc, err := overlayValue(&node)
+	if err != nil {
+		return nil, nil, err
+	}
+	nodes := make(map[uintptr]overlayNode)
+	recordOverlayNodes(&node, doc, nodes)
+
+	for _, overlay := range p.Overlays {
+		for _, action := range overlay.Actions {
+			if err := p.applyOverlayAction(doc, action); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	patched, err := json.Marshal(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return patched, overlayPositions(doc, nodes), nil
+}
+
+// overlayNode is the YAML node a container of an overlaid document was converted from.
+type overlayNode struct {
+	value any // Keeps the container alive, so its address is not reused
+	node  *yaml.Node
+}
+
+// containerID identifies the map[string]any objects and *[]any arrays of an
+// overlaid document, or returns 0 for other values.
+func containerID(value any) uintptr {
+	switch value.(type) {
+	case map[string]any, *[]any:
+		return reflect.ValueOf(value).Pointer()
+	}
+	return 0
+}
+
+// recordOverlayNodes maps the containers converted from a node by overlayValue to their nodes.
+func recordOverlayNodes(node *yaml.Node, value any, nodes map[uintptr]overlayNode) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			recordOverlayNodes(node.Content[0], value, nodes)
+		}
+	case yaml.AliasNode:
+		recordOverlayNodes(node.Alias, value, nodes)
+	case yaml.MappingNode:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+		nodes[containerID(object)] = overlayNode{value: object, node: node}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			recordOverlayNodes(node.Content[i+1], object[node.Content[i].Value], nodes)
+		}
+	case yaml.SequenceNode:
+		array, ok := value.(*[]any)
+		if !ok {
+			return
+		}
+		nodes[containerID(array)] = overlayNode{value: array, node: node}
+		for i, item := range node.Content {
+			if i < len(*array) {
+				recordOverlayNodes(item, (*array)[i], nodes)
+			}
+		}
+	}
+}
+
+// overlayPositions locates the elements of an overlaid document in the raw
+// document, by JSON pointer. Like with nodePositions, object members are
+// located by their key and array items by their first key.
+// Elements added by the overlays have no position.
+func overlayPositions(doc any, nodes map[uintptr]overlayNode) map[string]openapi3.Location {
+	positions := make(map[string]openapi3.Location)
+
+	var walk func(value any, pointer string)
+	walk = func(value any, pointer string) {
+		switch v := value.(type) {
+		case map[string]any:
+			original, known := nodes[containerID(v)]
+			for key, item := range v {
+				itemPointer := pointer + "/" + escapePointer(key)
+				if known {
+					for i := 0; i+1 < len(original.node.Content); i += 2 {
+						if keyNode := original.node.Content[i]; keyNode.Value == key {
+							positions[itemPointer] = openapi3.Location{Line: keyNode.Line, Column: keyNode.Column}
+						}
+					}
+				}
+				walk(item, itemPointer)
+			}
+		case *[]any:
+			for i, item := range *v {
+				itemPointer := fmt.Sprintf("%s/%d", pointer, i)
+				if original, ok := nodes[containerID(item)]; ok && original.node.Kind == yaml.MappingNode && len(original.node.Content) > 0 {
+					first := original.node.Content[0]
+					positions[itemPointer] = openapi3.Location{Line: first.Line, Column: first.Column}
+				}
+				walk(item, itemPointer)
+			}
+		}
+	}
+	walk(doc, "#")
+	return positions
+}
+
+func (p *Parser) applyOverlayAction(doc any, action OverlayAction) error {
+	nodes := action.path.selectNodes(doc)
+	if len(nodes) == 0 {
+		p.ValidationWarnings = append(p.ValidationWarnings, fmt.Sprintf("Overlay target %s matched nothing", action.Target))
+		return nil
+	}
+
+	if action.Remove {
+		for _, node := range sortedNodes(nodes) {
+			switch parent := node.parent.(type) {
+			case map[string]any:
+				delete(parent, node.key)
+			case *[]any:
+				*parent = append((*parent)[:node.index], (*parent)[node.index+1:]...)
+			default:
+				return fmt.Errorf("overlay target %s: cannot remove the document root", action.Target)
+			}
+		}
+		return nil
+	}
+
+	if action.Update.Kind == 0 {
+		return nil
+	}
+	update, err := overlayValue(&action.Update)
+	if err != nil {
+		return fmt.Errorf("overlay target %s: %w", action.Target, err)
+	}
+	for _, node := range nodes {
+		switch target := node.value.(type) {
+		case map[string]any:
+			if _, ok := update.(map[string]any); !ok {
+				return fmt.Errorf("overlay target %s: an object can only be updated with an object", action.Target)
+			}
+			mergeOverlayValue(target, copyOverlayValue(update).(map[string]any))
+		case *[]any:
+			*target = append(*target, copyOverlayValue(update))
+		default:
+			switch parent := node.parent.(type) {
+			case map[string]any:
+				parent[node.key] = copyOverlayValue(update)
+			case *[]any:
+				(*parent)[node.index] = copyOverlayValue(update)
+			}
+		}
+	}
+	return nil
+}
+
+// mergeOverlayValue merges an update object into a target object: nested
+// objects are merged, arrays appended and other values replaced.
+func mergeOverlayValue(target, update map[string]any) {
+	for key, value := range update {
+		switch v := value.(type) {
+		case map[string]any:
+			if existing, ok := target[key].(map[string]any); ok {
+				mergeOverlayValue(existing, v)
+				continue
+			}
+		case *[]any:
+			if existing, ok := target[key].(*[]any); ok {
+				*existing = append(*existing, *v...)
+				continue
+			}
+		}
+		target[key] = value
+	}
+}
+
+// overlayValue converts a YAML node to map[string]any objects, *[]any arrays
+// and scalars, so that the selected nodes can be modified in place.
+func overlayValue(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return overlayValue(node.Content[0])
+	case yaml.AliasNode:
+		return overlayValue(node.Alias)
+	case yaml.MappingNode:
+		object := make(map[string]any, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value, err := overlayValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			object[node.Content[i].Value] = value
+		}
+		return object, nil
+	case yaml.SequenceNode:
+		array := make([]any, 0, len(node.Content))
+		for _, item := range node.Content {
+			value, err := overlayValue(item)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		return &array, nil
+	default:
+		// Timestamps stay strings, as in JSON
+		if node.Tag == "!!timestamp" {
+			return node.Value, nil
+		}
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return nil, fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		return value, nil
+	}
+}
+
+// copyOverlayValue deep copies a value, so an update applied to several
+// targets is not shared between them.
+func copyOverlayValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		object := make(map[string]any, len(v))
+		for key, item := range v {
+			object[key] = copyOverlayValue(item)
+		}
+		return object
+	case *[]any:
+		array := make([]any, len(*v))
+		for i, item := range *v {
+			array[i] = copyOverlayValue(item)
+		}
+		return &array
+	default:
+		return v
+	}
+}
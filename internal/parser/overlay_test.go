@@ -0,0 +1,256 @@
+package parser
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const overlayDoc = `tags:
+  - name: pets
+  - name: store
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          required: false
+        - name: trace
+          in: header
+          required: true
+    post:
+      parameters:
+        - name: dryRun
+          in: query
+          required: true
+`
+
+func overlayTestDoc(t *testing.T) any {
+	t.Helper()
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(overlayDoc), &node); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := overlayValue(&node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// selectedNames returns the "name" members of the selected objects.
+func selectedNames(nodes []jsonNode) []string {
+	var names []string
+	for _, node := range nodes {
+		if object, ok := node.value.(map[string]any); ok {
+			names = append(names, object["name"].(string))
+		}
+	}
+	return names
+}
+
+func Test_jsonPath_selectNodes(t *testing.T) {
+	doc := overlayTestDoc(t)
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"$.tags[0]", []string{"pets"}},
+		{"$.tags[-1]", []string{"store"}},
+		{"$.tags[0,1]", []string{"pets", "store"}},
+		{"$.tags[*]", []string{"pets", "store"}},
+		{"$.paths['/pets'].get.parameters[*]", []string{"limit", "trace"}},
+		{`$.paths["/pets"].*.parameters[0]`, []string{"limit", "dryRun"}},
+		{"$..parameters[?(@.in == 'query')]", []string{"limit", "dryRun"}},
+		{"$..parameters[?@.in == 'query' && @.required == true]", []string{"dryRun"}},
+		{"$..parameters[?@.in == 'header' || @.name == 'limit']", []string{"limit", "trace"}},
+		{"$..parameters[?@.required != false]", []string{"trace", "dryRun"}},
+		{"$.tags[?@.name > 'q']", []string{"store"}},
+		{"$.tags[?!@.description]", []string{"pets", "store"}},
+		{"$.tags[5]", nil},
+		{"$.missing.name", nil},
+	}
+	for _, tt := range tests {
+		path, err := parseJSONPath(tt.expr)
+		if err != nil {
+			t.Errorf("parseJSONPath(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := selectedNames(path.selectNodes(doc)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s selects %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func Test_parseJSONPath_invalid(t *testing.T) {
+	for _, expr := range []string{
+		"paths",
+		"$.",
+		"$..",
+		"$.tags[0",
+		"$.tags[a]",
+		"$.tags[?name == 'pets']",
+		"$.tags[?@.name == pets]",
+		"$tags",
+	} {
+		if _, err := parseJSONPath(expr); err == nil {
+			t.Errorf("parseJSONPath(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func Test_applyOverlays_updateAndRemove(t *testing.T) {
+	p := NewParser()
+	loaded, err := LoadOverlay(writeTestFile(t, "overlay.yaml", `overlay: 1.0.0
+info:
+  title: Patch
+  version: "1"
+actions:
+  - target: $.paths['/pets'].*
+    update:
+      tags: [pets]
+  - target: $.tags
+    update:
+      name: admin
+  - target: $.tags[0].name
+    update: animals
+  - target: $..parameters[?@.in == 'header']
+    remove: true
+  - target: $.paths['/pets'].post.parameters[?@.name == 'dryRun'].required
+    update: false
+  - target: $.paths['/unknown']
+    remove: true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Overlays = append(p.Overlays, loaded)
+
+	data, positions, err := p.applyOverlays([]byte(overlayDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if positions == nil {
+		t.Error("applyOverlays returned no positions")
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"tags": []any{
+			map[string]any{"name": "animals"},
+			map[string]any{"name": "store"},
+			map[string]any{"name": "admin"},
+		},
+		"paths": map[string]any{
+			"/pets": map[string]any{
+				"get": map[string]any{
+					"tags": []any{"pets"},
+					"parameters": []any{
+						map[string]any{"name": "limit", "in": "query", "required": false},
+					},
+				},
+				"post": map[string]any{
+					"tags": []any{"pets"},
+					"parameters": []any{
+						map[string]any{"name": "dryRun", "in": "query", "required": false},
+					},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("patched document = %v\nwant %v", got, want)
+	}
+	if len(p.ValidationWarnings) != 1 || !strings.Contains(p.ValidationWarnings[0], "$.paths['/unknown']") {
+		t.Errorf("warnings = %v, want one for the unmatched target", p.ValidationWarnings)
+	}
+}
+
+func Test_applyOverlays_updateIsNotShared(t *testing.T) {
+	p := NewParser()
+	loaded, err := LoadOverlay(writeTestFile(t, "overlay.yaml", `overlay: 1.0.0
+actions:
+  - target: $.tags[*]
+    update:
+      x-labels: [a]
+  - target: $.tags[0].x-labels
+    update: b
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Overlays = append(p.Overlays, loaded)
+
+	data, _, err := p.applyOverlays([]byte(overlayDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Tags []struct {
+			Labels []string `json:"x-labels"`
+		} `json:"tags"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Tags) != 2 || !reflect.DeepEqual(got.Tags[0].Labels, []string{"a", "b"}) || !reflect.DeepEqual(got.Tags[1].Labels, []string{"a"}) {
+		t.Errorf("tags = %+v, want the update copied to each target", got.Tags)
+	}
+}
+
+func Test_applyOverlays_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		overlay string
+		want    string
+	}{
+		{
+			name:    "remove root",
+			overlay: "overlay: 1.0.0\nactions:\n  - target: $\n    remove: true\n",
+			want:    "cannot remove the document root",
+		},
+		{
+			name:    "object with scalar",
+			overlay: "overlay: 1.0.0\nactions:\n  - target: $.paths\n    update: none\n",
+			want:    "an object can only be updated with an object",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser()
+			loaded, err := LoadOverlay(writeTestFile(t, "overlay.yaml", tt.overlay))
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.Overlays = append(p.Overlays, loaded)
+			if _, _, err := p.applyOverlays([]byte(overlayDoc)); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_LoadOverlay_invalid(t *testing.T) {
+	tests := []struct {
+		overlay string
+		want    string
+	}{
+		{"overlay: 2.0.0\nactions: []\n", "unsupported overlay"},
+		{"actions: []\n", "unsupported overlay"},
+		{"overlay: 1.0.0\nactions:\n  - target: paths\n", "action 1"},
+		{"overlay: [1.0.0\n", "failed to parse overlay"},
+	}
+	for _, tt := range tests {
+		_, err := LoadOverlay(writeTestFile(t, "overlay.yaml", tt.overlay))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("LoadOverlay(%q) error = %v, want %q", tt.overlay, err, tt.want)
+		}
+	}
+}
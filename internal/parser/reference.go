@@ -0,0 +1,234 @@
+package parser
+
+import (
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/xseman/openapi-generator/internal/codegen"
+)
+
+// referenceGraph maps every component schema to the component schemas it references.
+type referenceGraph map[string][]string
+
+// buildReferenceGraph collects the $ref edges between component schemas.
+func (p *Parser) buildReferenceGraph() referenceGraph {
+	graph := make(referenceGraph)
+	if p.Doc == nil || p.Doc.Components == nil {
+		return graph
+	}
+
+	for name, schemaRef := range p.Doc.Components.Schemas {
+		if schemaRef == nil || schemaRef.Value == nil {
+			continue
+		}
+		refs := make(map[string]bool)
+		collectSchemaRefs(schemaRef.Value, refs, make(map[*openapi3.Schema]bool))
+		graph[name] = sortedKeys(refs)
+	}
+	return graph
+}
+
+// collectSchemaRefs adds the names of the schemas referenced by a schema to refs.
+// References are recorded but not followed; inline schemas are visited once.
+func collectSchemaRefs(schema *openapi3.Schema, refs map[string]bool, visited map[*openapi3.Schema]bool) {
+	if schema == nil || visited[schema] {
+		return
+	}
+	visited[schema] = true
+
+	visit := func(schemaRef *openapi3.SchemaRef) {
+		if schemaRef == nil {
+			return
+		}
+		if schemaRef.Ref != "" {
+			refs[extractRefName(schemaRef.Ref)] = true
+			return
+		}
+		collectSchemaRefs(schemaRef.Value, refs, visited)
+	}
+
+	for _, propRef := range schema.Properties {
+		visit(propRef)
+	}
+	visit(schema.Items)
+	visit(schema.AdditionalProperties.Schema)
+	visit(schema.Not)
+	for _, members := range []openapi3.SchemaRefs{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for _, member := range members {
+			visit(member)
+		}
+	}
+}
+
+// stronglyConnected returns the strongly connected components of the graph,
+// using Tarjan's algorithm. Components come out dependencies first.
+func (g referenceGraph) stronglyConnected() [][]string {
+	names := make([]string, 0, len(g))
+	for name := range g {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	index := make(map[string]int)
+	lowLink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	var connect func(name string)
+	connect = func(name string) {
+		index[name] = len(index)
+		lowLink[name] = index[name]
+		stack = append(stack, name)
+		onStack[name] = true
+
+		for _, ref := range g[name] {
+			if _, ok := g[ref]; !ok {
+				continue // Unresolved reference
+			}
+			if _, seen := index[ref]; !seen {
+				connect(ref)
+				lowLink[name] = min(lowLink[name], lowLink[ref])
+			} else if onStack[ref] {
+				lowLink[name] = min(lowLink[name], index[ref])
+			}
+		}
+
+		if lowLink[name] == index[name] {
+			var component []string
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == name {
+					break
+				}
+			}
+			sort.Strings(component)
+			components = append(components, component)
+		}
+	}
+
+	for _, name := range names {
+		if _, seen := index[name]; !seen {
+			connect(name)
+		}
+	}
+	return components
+}
+
+// markReferences flags self and circular references on models and their properties.
+// A reference is circular when it points back into the strongly connected
+// component of the model, i.e. the referenced schema (indirectly) references the model.
+func (p *Parser) markReferences(models []*codegen.CodegenModel) {
+	graph := p.buildReferenceGraph()
+
+	componentOf := make(map[string]int)
+	cyclic := make(map[int]bool)
+	for i, component := range graph.stronglyConnected() {
+		for _, name := range component {
+			componentOf[name] = i
+		}
+		cyclic[i] = len(component) > 1
+	}
+
+	for _, model := range models {
+		schemaRef := p.Doc.Components.Schemas[model.SchemaName]
+		if schemaRef == nil || schemaRef.Value == nil {
+			continue
+		}
+		component := componentOf[model.SchemaName]
+
+		for _, ref := range graph[model.SchemaName] {
+			if ref == model.SchemaName {
+				model.IsSelfReference = true
+				cyclic[component] = true
+			}
+		}
+		model.IsCircularReference = cyclic[component]
+
+		for _, prop := range model.AllVars {
+			propRef := findPropertySchema(schemaRef.Value, prop.BaseName, make(map[*openapi3.Schema]bool))
+			if propRef == nil {
+				continue
+			}
+
+			refs := make(map[string]bool)
+			if propRef.Ref != "" {
+				refs[extractRefName(propRef.Ref)] = true
+			} else {
+				collectSchemaRefs(propRef.Value, refs, make(map[*openapi3.Schema]bool))
+			}
+
+			for ref := range refs {
+				if ref == model.SchemaName {
+					prop.IsSelfReference = true
+				}
+				if c, ok := componentOf[ref]; ok && c == component && cyclic[component] {
+					prop.IsCircularReference = true
+				}
+			}
+		}
+	}
+}
+
+// findPropertySchema returns the schema of a property declared by a schema or its allOf members.
+func findPropertySchema(schema *openapi3.Schema, name string, visited map[*openapi3.Schema]bool) *openapi3.SchemaRef {
+	if schema == nil || visited[schema] {
+		return nil
+	}
+	visited[schema] = true
+
+	if propRef, ok := schema.Properties[name]; ok {
+		return propRef
+	}
+	for _, member := range schema.AllOf {
+		if member == nil {
+			continue
+		}
+		if propRef := findPropertySchema(member.Value, name, visited); propRef != nil {
+			return propRef
+		}
+	}
+	return nil
+}
+
+// ModelDependencyOrder returns the models ordered so that every model comes after
+// the models it references. Models referencing each other in a cycle are kept
+// together in alphabetical order; generators needing forward declarations or lazy
+// references can detect them with IsCircularReference.
+func (p *Parser) ModelDependencyOrder(models []*codegen.CodegenModel) []*codegen.CodegenModel {
+	bySchemaName := make(map[string]*codegen.CodegenModel, len(models))
+	for _, model := range models {
+		bySchemaName[model.SchemaName] = model
+	}
+
+	ordered := make([]*codegen.CodegenModel, 0, len(models))
+	for _, component := range p.buildReferenceGraph().stronglyConnected() {
+		for _, name := range component {
+			if model, ok := bySchemaName[name]; ok {
+				ordered = append(ordered, model)
+				delete(bySchemaName, name)
+			}
+		}
+	}
+
+	// Models without a component schema keep their relative order at the end
+	for _, model := range models {
+		if _, ok := bySchemaName[model.SchemaName]; ok {
+			ordered = append(ordered, model)
+		}
+	}
+	return ordered
+}
+
+// sortedKeys returns the keys of a set or map in sorted order.
+func sortedKeys[V any](set map[string]V) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
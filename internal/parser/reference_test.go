@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestModelDependencyOrder(t *testing.T) {
+	p := loadTestSpec(t, nil, `
+openapi: 3.0.3
+info: {title: T, version: 1.0.0}
+paths: {}
+components:
+	schemas:
+		Alpha:
+			type: object
+			properties:
+				beta: {$ref: '#/components/schemas/Beta'}
+				delta: {$ref: '#/components/schemas/Delta'}
+		Beta:
+			type: object
+			properties:
+				gamma: {$ref: '#/components/schemas/Gamma'}
+		Gamma:
+			type: object
+			properties:
+				beta: {$ref: '#/components/schemas/Beta'}
+				delta: {$ref: '#/components/schemas/Delta'}
+		Delta:
+			type: object
+			properties:
+				next: {$ref: '#/components/schemas/Delta'}
+		Epsilon:
+			type: string
+`)
+	models, err := p.GetModels()
+	if err != nil {
+		t.Fatalf("GetModels: %v", err)
+	}
+
+	var names []string
+	for _, model := range p.ModelDependencyOrder(models) {
+		names = append(names, model.SchemaName)
+	}
+	// Delta before the Beta/Gamma cycle referencing it, both before Alpha
+	want := []string{"Delta", "Beta", "Gamma", "Alpha", "Epsilon"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("ModelDependencyOrder = %v, want %v", names, want)
+	}
+
+	circular := make(map[string]bool)
+	for _, model := range models {
+		circular[model.SchemaName] = model.IsCircularReference
+	}
+	wantCircular := map[string]bool{"Alpha": false, "Beta": true, "Gamma": true, "Delta": true, "Epsilon": false}
+	if !reflect.DeepEqual(circular, wantCircular) {
+		t.Errorf("IsCircularReference = %v, want %v", circular, wantCircular)
+	}
+}
@@ -0,0 +1,275 @@
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Environment variables providing credentials for remote specs
+const (
+	EnvBearerToken   = "OPENAPI_GENERATOR_BEARER_TOKEN"
+	EnvBasicUser     = "OPENAPI_GENERATOR_BASIC_USER"
+	EnvBasicPassword = "OPENAPI_GENERATOR_BASIC_PASSWORD"
+)
+
+// DefaultRemoteTimeout bounds every request for a remote spec or reference.
+const DefaultRemoteTimeout = 30 * time.Second
+
+// RemoteOptions configures how remote specs and their external references are fetched.
+type RemoteOptions struct {
+	Headers   http.Header   // Sent to the origin of the spec and AuthHosts, e.g. Authorization
+	AuthHosts []string      // Further hosts receiving Headers, e.g. "schemas.example.com"
+	Timeout   time.Duration // Per request, DefaultRemoteTimeout when zero
+	CacheDir  string        // Keeps fetched documents for conditional and offline loading
+	Offline   bool          // Only read from CacheDir, never from the network
+}
+
+// ParseAuth parses Java-compatible authorization values, "name:value" headers
+// separated by commas whose values are URL-encoded, e.g.
+// "Authorization:Bearer%20token,X-Api-Key:secret". The bearer token or basic
+// credentials of the environment add an Authorization header, unless one is given.
+func ParseAuth(values []string) (http.Header, error) {
+	headers := make(http.Header)
+	for _, value := range values {
+		for _, pair := range strings.Split(value, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			name, headerValue, ok := strings.Cut(pair, ":")
+			name = strings.TrimSpace(name)
+			if !ok || name == "" {
+				return nil, fmt.Errorf("invalid auth %q (expected name:value)", pair)
+			}
+			decoded, err := url.QueryUnescape(strings.TrimSpace(headerValue))
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth %q: %w", pair, err)
+			}
+			headers.Add(name, decoded)
+		}
+	}
+
+	if headers.Get("Authorization") == "" {
+		if token := os.Getenv(EnvBearerToken); token != "" {
+			headers.Set("Authorization", "Bearer "+token)
+		} else if user := os.Getenv(EnvBasicUser); user != "" {
+			credentials := base64.StdEncoding.EncodeToString([]byte(user + ":" + os.Getenv(EnvBasicPassword)))
+			headers.Set("Authorization", "Basic "+credentials)
+		}
+	}
+	return headers, nil
+}
+
+// newLoader returns a loader resolving external references, fetching remote
+// ones like the spec itself. The positions of the referenced documents are
+// recorded, unless positions is nil.
+func (p *Parser) newLoader(positions positionIndex) *openapi3.Loader {
+	read := openapi3.ReadFromURIs(p.readFromHTTP, openapi3.ReadFromFile)
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = openapi3.URIMapCache(func(loader *openapi3.Loader, location *url.URL) ([]byte, error) {
+		data, err := read(loader, location)
+		if err == nil {
+			positions.record(uriLocation(location), data, nil)
+		}
+		return data, err
+	})
+	return loader
+}
+
+// uriLocation returns the URL of a remote document or the path of a file,
+// as locations of the loaded spec are given.
+func uriLocation(location *url.URL) string {
+	if location.Scheme == "http" || location.Scheme == "https" {
+		return location.String()
+	}
+	return filepath.FromSlash(location.Path)
+}
+
+// fetchSpec downloads a remote spec. Its origin receives the headers of the
+// remote options, like the hosts they are explicitly allowed for.
+func (p *Parser) fetchSpec(location string) ([]byte, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL: %w", err)
+	}
+	if p.specOrigins == nil {
+		p.specOrigins = make(map[string]bool)
+	}
+	p.specOrigins[origin(u)] = true
+	return p.fetch(location)
+}
+
+// sendsHeadersTo reports whether the headers of the remote options may be sent to a location.
+func (p *Parser) sendsHeadersTo(location *url.URL) bool {
+	if p.specOrigins[origin(location)] {
+		return true
+	}
+	for _, host := range p.Remote.AuthHosts {
+		if strings.EqualFold(host, location.Host) || strings.EqualFold(host, location.Hostname()) {
+			return true
+		}
+	}
+	return false
+}
+
+// origin returns the scheme and host of a location, e.g. "https://api.example.com:8443".
+func origin(location *url.URL) string {
+	return strings.ToLower(location.Scheme + "://" + location.Host)
+}
+
+// readFromHTTP is an openapi3.ReadFromURIFunc for http(s) locations.
+func (p *Parser) readFromHTTP(_ *openapi3.Loader, location *url.URL) ([]byte, error) {
+	if location.Scheme != "http" && location.Scheme != "https" {
+		return nil, openapi3.ErrURINotSupported
+	}
+	return p.fetch(location.String())
+}
+
+// cacheEntry describes a cached document, stored next to its body.
+type cacheEntry struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// fetch downloads a remote document. With a cache directory, the cached copy
+// is revalidated by ETag or modification date, and used when offline or when
+// the server cannot be reached.
+func (p *Parser) fetch(location string) ([]byte, error) {
+	opts := p.Remote
+	cached, entry := p.readCache(location)
+	if opts.Offline {
+		if cached == nil {
+			return nil, fmt.Errorf("%s is not cached in %s (offline)", location, opts.CacheDir)
+		}
+		return cached, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, location, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if p.sendsHeadersTo(req.URL) {
+		for name, values := range opts.Headers {
+			for _, value := range values {
+				req.Header.Add(name, value)
+			}
+		}
+	}
+	if cached != nil {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = DefaultRemoteTimeout
+	}
+	client := &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			// Redirects keep the headers only while they stay on allowed hosts
+			if !p.sendsHeadersTo(req.URL) {
+				for name := range opts.Headers {
+					req.Header.Del(name)
+				}
+			}
+			return nil
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if cached != nil {
+			fmt.Fprintf(os.Stderr, "Warning: using cached %s: %v\n", location, err)
+			return cached, nil
+		}
+		return nil, fmt.Errorf("failed to fetch %s: %w", location, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return cached, nil
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("failed to fetch %s: %s", location, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	p.writeCache(location, data, cacheEntry{
+		URL:          location,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	})
+	return data, nil
+}
+
+// cachePath returns the file a document is cached in, without extension.
+func (p *Parser) cachePath(location string) string {
+	sum := sha256.Sum256([]byte(location))
+	return filepath.Join(p.Remote.CacheDir, hex.EncodeToString(sum[:]))
+}
+
+// readCache returns a cached document and its entry, or nil when not cached.
+func (p *Parser) readCache(location string) ([]byte, cacheEntry) {
+	var entry cacheEntry
+	if p.Remote.CacheDir == "" {
+		return nil, entry
+	}
+	base := p.cachePath(location)
+	meta, err := os.ReadFile(base + ".json")
+	if err != nil || json.Unmarshal(meta, &entry) != nil || entry.URL != location {
+		return nil, entry
+	}
+	data, err := os.ReadFile(base + ".body")
+	if err != nil {
+		return nil, entry
+	}
+	return data, entry
+}
+
+// writeCache stores a fetched document. Failures only cost the cache, so they are reported as warnings.
+// Documents fetched with credentials may be private, so only the user can read the cache.
+func (p *Parser) writeCache(location string, data []byte, entry cacheEntry) {
+	if p.Remote.CacheDir == "" {
+		return
+	}
+	meta, err := json.Marshal(entry)
+	if err == nil {
+		err = os.MkdirAll(p.Remote.CacheDir, 0700)
+	}
+	base := p.cachePath(location)
+	if err == nil {
+		err = os.WriteFile(base+".body", data, 0600)
+	}
+	if err == nil {
+		err = os.WriteFile(base+".json", meta, 0600)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache %s: %v\n", location, err)
+	}
+}
@@ -0,0 +1,241 @@
+package parser
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingServer serves fixed documents and records the requests it receives.
+type recordingServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func newRecordingServer(t *testing.T, handler http.HandlerFunc) *recordingServer {
+	t.Helper()
+	s := &recordingServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r)
+		s.mu.Unlock()
+		handler(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// lastRequest returns the last request received for a path.
+func (s *recordingServer) lastRequest(t *testing.T, path string) *http.Request {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.requests) - 1; i >= 0; i-- {
+		if s.requests[i].URL.Path == path {
+			return s.requests[i]
+		}
+	}
+	t.Fatalf("no request for %s", path)
+	return nil
+}
+
+// count returns the number of requests received.
+func (s *recordingServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+const remotePetSchema = `
+type: object
+properties:
+  name:
+    type: string
+`
+
+func TestLoadFromURLAuthHeaders(t *testing.T) {
+	schemas := newRecordingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(remotePetSchema))
+	})
+	spec := `
+openapi: 3.0.3
+info:
+  title: Remote
+  version: "1"
+paths: {}
+components:
+  schemas:
+    Pet:
+      $ref: "` + schemas.URL + `/pet.yaml"
+    Local:
+      $ref: "./local.yaml"
+`
+	api := newRecordingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/openapi.yaml":
+			_, _ = w.Write([]byte(spec))
+		case "/redirect.yaml":
+			http.Redirect(w, r, schemas.URL+"/redirected.yaml", http.StatusFound)
+		default:
+			_, _ = w.Write([]byte(remotePetSchema))
+		}
+	})
+	headers := http.Header{"Authorization": {"Bearer secret"}, "X-Api-Key": {"key"}}
+
+	tests := []struct {
+		name         string
+		authHosts    []string
+		wantExternal bool
+	}{
+		{name: "origin only"},
+		{name: "allowed host", authHosts: []string{mustParseURL(t, schemas.URL).Host}, wantExternal: true},
+		{name: "allowed host name", authHosts: []string{"127.0.0.1"}, wantExternal: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser()
+			p.Remote = RemoteOptions{Headers: headers, AuthHosts: tt.authHosts}
+			if err := p.LoadFromURL(api.URL + "/openapi.yaml"); err != nil {
+				t.Fatalf("LoadFromURL: %v", err)
+			}
+
+			for _, path := range []string{"/openapi.yaml", "/local.yaml"} {
+				req := api.lastRequest(t, path)
+				if req.Header.Get("Authorization") != "Bearer secret" || req.Header.Get("X-Api-Key") != "key" {
+					t.Errorf("%s headers = %v", path, req.Header)
+				}
+			}
+			req := schemas.lastRequest(t, "/pet.yaml")
+			if got := req.Header.Get("Authorization") != ""; got != tt.wantExternal {
+				t.Errorf("external Authorization sent = %v, want %v", got, tt.wantExternal)
+			}
+			if got := req.Header.Get("X-Api-Key") != ""; got != tt.wantExternal {
+				t.Errorf("external X-Api-Key sent = %v, want %v", got, tt.wantExternal)
+			}
+		})
+	}
+
+	t.Run("redirect to another host", func(t *testing.T) {
+		p := NewParser()
+		p.Remote = RemoteOptions{Headers: headers}
+		if _, err := p.fetchSpec(api.URL + "/redirect.yaml"); err != nil {
+			t.Fatalf("fetchSpec: %v", err)
+		}
+		if req := schemas.lastRequest(t, "/redirected.yaml"); req.Header.Get("X-Api-Key") != "" {
+			t.Errorf("redirect kept the headers: %v", req.Header)
+		}
+	})
+}
+
+func TestFetchTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := newRecordingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	// Unblock the handler before the server waits for it to close
+	t.Cleanup(func() { close(release) })
+
+	p := NewParser()
+	p.Remote = RemoteOptions{Timeout: 50 * time.Millisecond}
+	start := time.Now()
+	_, err := p.fetch(server.URL + "/openapi.yaml")
+	if err == nil {
+		t.Fatal("fetch succeeded, want a timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("fetch returned after %v", elapsed)
+	}
+}
+
+func TestFetchCache(t *testing.T) {
+	const etag = `"v1"`
+	var mu sync.Mutex
+	body := "openapi: 3.0.3\n"
+	server := newRecordingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(body))
+	})
+	location := server.URL + "/openapi.yaml"
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+
+	p := NewParser()
+	p.Remote = RemoteOptions{CacheDir: cacheDir}
+	if data, err := p.fetch(location); err != nil || string(data) != body {
+		t.Fatalf("fetch = %q, %v", data, err)
+	}
+
+	t.Run("permissions", func(t *testing.T) {
+		if info, err := os.Stat(cacheDir); err != nil || info.Mode().Perm() != 0700 {
+			t.Errorf("cache directory mode = %v, %v", info.Mode().Perm(), err)
+		}
+		for _, ext := range []string{".body", ".json"} {
+			info, err := os.Stat(p.cachePath(location) + ext)
+			if err != nil || info.Mode().Perm() != 0600 {
+				t.Errorf("cache file %s mode = %v, %v", ext, info.Mode().Perm(), err)
+			}
+		}
+	})
+
+	t.Run("revalidation", func(t *testing.T) {
+		// A changed body proves the cached copy is used after 304 Not Modified
+		mu.Lock()
+		body = "changed"
+		mu.Unlock()
+		data, err := p.fetch(location)
+		if err != nil || string(data) != "openapi: 3.0.3\n" {
+			t.Fatalf("fetch = %q, %v", data, err)
+		}
+		if got := server.lastRequest(t, "/openapi.yaml").Header.Get("If-None-Match"); got != etag {
+			t.Errorf("If-None-Match = %q", got)
+		}
+	})
+
+	t.Run("offline", func(t *testing.T) {
+		offline := NewParser()
+		offline.Remote = RemoteOptions{CacheDir: cacheDir, Offline: true}
+		requests := server.count()
+		data, err := offline.fetch(location)
+		if err != nil || string(data) != "openapi: 3.0.3\n" {
+			t.Fatalf("fetch = %q, %v", data, err)
+		}
+		if _, err := offline.fetch(server.URL + "/other.yaml"); err == nil || !strings.Contains(err.Error(), "offline") {
+			t.Errorf("uncached fetch error = %v", err)
+		}
+		if server.count() != requests {
+			t.Error("offline fetch reached the server")
+		}
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		server.Close()
+		data, err := p.fetch(location)
+		if err != nil || string(data) != "openapi: 3.0.3\n" {
+			t.Fatalf("fetch = %q, %v", data, err)
+		}
+		if _, err := p.fetch(server.URL + "/other.yaml"); err == nil {
+			t.Error("uncached fetch from a closed server succeeded")
+		}
+	})
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/xseman/openapi-generator/internal/codegen"
+)
+
+// applySecurity computes the effective security of an operation.
+// Operation-level security replaces the document-level security, and an empty
+// list (security: []) removes authentication from the operation entirely.
+func (p *Parser) applySecurity(co *codegen.CodegenOperation, op *openapi3.Operation) {
+	var requirements openapi3.SecurityRequirements
+	if op.Security != nil {
+		requirements = *op.Security
+	} else if p.Doc != nil && len(p.Doc.Security) > 0 {
+		requirements = p.Doc.Security
+		co.IsSecurityInherited = true
+	}
+
+	seen := make(map[string]*codegen.CodegenSecurity)
+	for _, requirement := range requirements {
+		req := &codegen.CodegenSecurityRequirement{}
+
+		// Sort scheme names for deterministic output
+		names := make([]string, 0, len(requirement))
+		for name := range requirement {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			sec := p.securityRequirementToCodegen(name, requirement[name])
+			req.AuthMethods = append(req.AuthMethods, sec)
+
+			// AuthMethods holds every scheme once, with the scopes of all alternatives
+			if existing, ok := seen[name]; ok {
+				existing.Scopes = mergeScopes(existing.Scopes, sec.Scopes)
+				existing.HasScopes = len(existing.Scopes) > 0
+				continue
+			}
+			union := *sec
+			union.Scopes = append([]map[string]any(nil), sec.Scopes...)
+			seen[name] = &union
+			co.AuthMethods = append(co.AuthMethods, &union)
+		}
+
+		req.HasAuthMethods = len(req.AuthMethods) > 0
+		req.IsAnonymous = !req.HasAuthMethods
+		if req.IsAnonymous {
+			co.IsAuthOptional = true
+		}
+		co.SecurityRequirements = append(co.SecurityRequirements, req)
+	}
+
+	co.HasAuthMethods = len(co.AuthMethods) > 0
+}
+
+// securityRequirementToCodegen returns the scheme referenced by a security requirement,
+// restricted to the scopes the requirement asks for.
+func (p *Parser) securityRequirementToCodegen(name string, scopes []string) *codegen.CodegenSecurity {
+	var sec *codegen.CodegenSecurity
+	var available map[string]string
+	if p.Doc != nil && p.Doc.Components != nil {
+		if schemeRef := p.Doc.Components.SecuritySchemes[name]; schemeRef != nil && schemeRef.Value != nil {
+			sec = p.securitySchemeToCodegen(name, schemeRef.Value)
+			available = flowScopes(schemeRef.Value)
+		}
+	}
+	if sec == nil {
+		// Undefined scheme, keep what the requirement tells us
+		sec = &codegen.CodegenSecurity{Name: name}
+	}
+
+	sec.Scopes = make([]map[string]any, 0, len(scopes))
+	for _, scope := range scopes {
+		sec.Scopes = append(sec.Scopes, map[string]any{
+			"scope":       scope,
+			"description": available[scope],
+		})
+	}
+	sec.HasScopes = len(sec.Scopes) > 0
+
+	return sec
+}
+
+// flowScopes returns the scopes declared by all OAuth flows of a scheme.
+func flowScopes(scheme *openapi3.SecurityScheme) map[string]string {
+	scopes := make(map[string]string)
+	if scheme.Flows == nil {
+		return scopes
+	}
+	for _, flow := range []*openapi3.OAuthFlow{
+		scheme.Flows.AuthorizationCode,
+		scheme.Flows.Implicit,
+		scheme.Flows.Password,
+		scheme.Flows.ClientCredentials,
+	} {
+		if flow == nil {
+			continue
+		}
+		for scope, desc := range flow.Scopes {
+			scopes[scope] = desc
+		}
+	}
+	return scopes
+}
+
+// mergeScopes appends the scopes of b that are missing from a.
+func mergeScopes(a, b []map[string]any) []map[string]any {
+	present := make(map[any]bool, len(a))
+	for _, scope := range a {
+		present[scope["scope"]] = true
+	}
+	for _, scope := range b {
+		if !present[scope["scope"]] {
+			a = append(a, scope)
+			present[scope["scope"]] = true
+		}
+	}
+	return a
+}
@@ -0,0 +1,191 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xseman/openapi-generator/internal/codegen"
+)
+
+const securitySpec = `
+openapi: 3.0.3
+info:
+	title: Security
+	version: "1"
+security:
+	- api_key: []
+	- oauth: [read]
+paths:
+	/inherit:
+		get:
+			operationId: inherit
+			responses:
+				"200":
+					description: ok
+	/override:
+		get:
+			operationId: override
+			security:
+				- basic: []
+					oauth: [write]
+				- oauth: [read, write]
+			responses:
+				"200":
+					description: ok
+	/none:
+		get:
+			operationId: none
+			security: []
+			responses:
+				"200":
+					description: ok
+	/optional:
+		get:
+			operationId: optional
+			security:
+				- {}
+				- api_key: []
+			responses:
+				"200":
+					description: ok
+	/undefined:
+		get:
+			operationId: undefinedScheme
+			security:
+				- missing: [scope]
+			responses:
+				"200":
+					description: ok
+components:
+	securitySchemes:
+		api_key:
+			type: apiKey
+			in: header
+			name: X-Api-Key
+		basic:
+			type: http
+			scheme: basic
+		oauth:
+			type: oauth2
+			flows:
+				implicit:
+					authorizationUrl: https://example.com/auth
+					scopes:
+						read: Read pets
+						write: Write pets
+`
+
+// securityOperation returns the converted operation with the given ID.
+func securityOperation(t *testing.T, operationID string) *codegen.CodegenOperation {
+	t.Helper()
+	p := NewParser()
+	p.SkipValidation = true
+	loadTestSpec(t, p, securitySpec)
+	byTag, err := p.GetOperations()
+	if err != nil {
+		t.Fatalf("GetOperations: %v", err)
+	}
+	for _, op := range byTag[defaultTag] {
+		if op.OperationId == operationID {
+			return op
+		}
+	}
+	t.Fatalf("no operation %s", operationID)
+	return nil
+}
+
+// requirementNames returns the scheme names of every requirement, "{}" for anonymous ones.
+func requirementNames(op *codegen.CodegenOperation) [][]string {
+	var names [][]string
+	for _, req := range op.SecurityRequirements {
+		if req.IsAnonymous {
+			names = append(names, []string{"{}"})
+			continue
+		}
+		var schemes []string
+		for _, sec := range req.AuthMethods {
+			schemes = append(schemes, sec.Name)
+		}
+		names = append(names, schemes)
+	}
+	return names
+}
+
+// scopeNames returns the names of the scopes of a scheme.
+func scopeNames(sec *codegen.CodegenSecurity) []string {
+	var scopes []string
+	for _, scope := range sec.Scopes {
+		scopes = append(scopes, scope["scope"].(string))
+	}
+	return scopes
+}
+
+func Test_applySecurity_inherit(t *testing.T) {
+	op := securityOperation(t, "inherit")
+	if !op.IsSecurityInherited || op.IsAuthOptional || !op.HasAuthMethods {
+		t.Errorf("inherited = %v, optional = %v, hasAuthMethods = %v", op.IsSecurityInherited, op.IsAuthOptional, op.HasAuthMethods)
+	}
+	if got, want := requirementNames(op), [][]string{{"api_key"}, {"oauth"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("requirements = %v, want %v", got, want)
+	}
+	oauth := op.SecurityRequirements[1].AuthMethods[0]
+	if !oauth.IsOAuth || !reflect.DeepEqual(scopeNames(oauth), []string{"read"}) || oauth.Scopes[0]["description"] != "Read pets" {
+		t.Errorf("oauth = %+v", oauth)
+	}
+}
+
+func Test_applySecurity_override(t *testing.T) {
+	op := securityOperation(t, "override")
+	if op.IsSecurityInherited || op.IsAuthOptional {
+		t.Errorf("inherited = %v, optional = %v", op.IsSecurityInherited, op.IsAuthOptional)
+	}
+	if got, want := requirementNames(op), [][]string{{"basic", "oauth"}, {"oauth"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("requirements = %v, want %v", got, want)
+	}
+
+	// Each alternative keeps its own scopes, the union merges them
+	if got := scopeNames(op.SecurityRequirements[0].AuthMethods[1]); !reflect.DeepEqual(got, []string{"write"}) {
+		t.Errorf("first alternative scopes = %v", got)
+	}
+	if got := scopeNames(op.SecurityRequirements[1].AuthMethods[0]); !reflect.DeepEqual(got, []string{"read", "write"}) {
+		t.Errorf("second alternative scopes = %v", got)
+	}
+	var union []string
+	for _, sec := range op.AuthMethods {
+		union = append(union, sec.Name)
+	}
+	if !reflect.DeepEqual(union, []string{"basic", "oauth"}) {
+		t.Errorf("auth methods = %v", union)
+	}
+	if got := scopeNames(op.AuthMethods[1]); !reflect.DeepEqual(got, []string{"write", "read"}) {
+		t.Errorf("union scopes = %v", got)
+	}
+}
+
+func Test_applySecurity_emptyList(t *testing.T) {
+	op := securityOperation(t, "none")
+	if op.HasAuthMethods || op.IsSecurityInherited || op.IsAuthOptional || len(op.SecurityRequirements) != 0 {
+		t.Errorf("operation with security: [] = %+v", op.SecurityRequirements)
+	}
+}
+
+func Test_applySecurity_anonymousRequirement(t *testing.T) {
+	op := securityOperation(t, "optional")
+	if !op.IsAuthOptional || !op.HasAuthMethods {
+		t.Errorf("optional = %v, hasAuthMethods = %v", op.IsAuthOptional, op.HasAuthMethods)
+	}
+	if got, want := requirementNames(op), [][]string{{"{}"}, {"api_key"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("requirements = %v, want %v", got, want)
+	}
+	if anonymous := op.SecurityRequirements[0]; anonymous.HasAuthMethods || len(anonymous.AuthMethods) != 0 {
+		t.Errorf("anonymous requirement = %+v", anonymous)
+	}
+}
+
+func Test_applySecurity_undefinedScheme(t *testing.T) {
+	op := securityOperation(t, "undefinedScheme")
+	sec := op.SecurityRequirements[0].AuthMethods[0]
+	if sec.Name != "missing" || sec.Type != "" || !reflect.DeepEqual(scopeNames(sec), []string{"scope"}) {
+		t.Errorf("undefined scheme = %+v", sec)
+	}
+}
@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/xseman/openapi-generator/internal/codegen"
+)
+
+// GetServers extracts the top-level servers of the spec.
+func (p *Parser) GetServers() []*codegen.CodegenServer {
+	if p.Doc == nil {
+		return nil
+	}
+	return serversToCodegen(p.Doc.Servers)
+}
+
+// serversToCodegen converts a list of OpenAPI servers to CodegenServers.
+func serversToCodegen(servers openapi3.Servers) []*codegen.CodegenServer {
+	if len(servers) == 0 {
+		return nil
+	}
+
+	var result []*codegen.CodegenServer
+	for _, server := range servers {
+		if server == nil {
+			continue
+		}
+		result = append(result, serverToCodegen(server))
+	}
+	return result
+}
+
+// serverToCodegen converts an OpenAPI server to a CodegenServer.
+func serverToCodegen(server *openapi3.Server) *codegen.CodegenServer {
+	cs := &codegen.CodegenServer{
+		URL:              server.URL,
+		Description:      server.Description,
+		VendorExtensions: convertExtensions(server.Extensions),
+	}
+
+	// Sort variable names for deterministic output
+	names := make([]string, 0, len(server.Variables))
+	for name := range server.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		variable := server.Variables[name]
+		if variable == nil {
+			continue
+		}
+		cs.Variables = append(cs.Variables, &codegen.CodegenServerVariable{
+			Name:             name,
+			Description:      variable.Description,
+			DefaultValue:     variable.Default,
+			EnumValues:       variable.Enum,
+			HasEnumValues:    len(variable.Enum) > 0,
+			VendorExtensions: convertExtensions(variable.Extensions),
+		})
+	}
+	cs.HasVariables = len(cs.Variables) > 0
+
+	return cs
+}
+
+// resolveServerURL substitutes the default values of the server variables into its URL.
+func resolveServerURL(server *openapi3.Server) string {
+	url := server.URL
+	for name, variable := range server.Variables {
+		if variable == nil {
+			continue
+		}
+		url = strings.ReplaceAll(url, "{"+name+"}", variable.Default)
+	}
+	return url
+}
@@ -0,0 +1,150 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func Test_resolveServerURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		server *openapi3.Server
+		want   string
+	}{
+		{
+			name:   "no variables",
+			server: &openapi3.Server{URL: "https://api.example.com/v1"},
+			want:   "https://api.example.com/v1",
+		},
+		{
+			name: "defaults",
+			server: &openapi3.Server{
+				URL: "{scheme}://{region}.example.com:{port}/{region}",
+				Variables: map[string]*openapi3.ServerVariable{
+					"scheme": {Default: "https"},
+					"region": {Default: "eu", Enum: []string{"eu", "us"}},
+					"port":   {Default: "443"},
+				},
+			},
+			want: "https://eu.example.com:443/eu",
+		},
+		{
+			name: "undeclared variable",
+			server: &openapi3.Server{
+				URL:       "https://{tenant}.example.com/{version}",
+				Variables: map[string]*openapi3.ServerVariable{"version": {Default: "v2"}, "missing": nil},
+			},
+			want: "https://{tenant}.example.com/v2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveServerURL(tt.server); got != tt.want {
+				t.Errorf("resolveServerURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetServers_variables(t *testing.T) {
+	p := loadTestSpec(t, nil, `
+openapi: 3.0.3
+info:
+	title: Servers
+	version: "1"
+servers:
+	- url: https://{region}.example.com/{version}
+		description: Regional
+		variables:
+			version:
+				default: v1
+			region:
+				default: eu
+				enum: [eu, us]
+				description: Data center
+	- url: http://localhost:8080
+paths: {}
+`)
+	servers := p.GetServers()
+	if len(servers) != 2 {
+		t.Fatalf("servers = %d, want 2", len(servers))
+	}
+	if got := p.GetBasePath(); got != "https://eu.example.com/v1" {
+		t.Errorf("GetBasePath() = %q", got)
+	}
+
+	regional := servers[0]
+	if regional.Description != "Regional" || !regional.HasVariables || len(regional.Variables) != 2 {
+		t.Fatalf("regional server = %+v", regional)
+	}
+	region, version := regional.Variables[0], regional.Variables[1]
+	if region.Name != "region" || version.Name != "version" {
+		t.Errorf("variables are not sorted: %s, %s", region.Name, version.Name)
+	}
+	if region.DefaultValue != "eu" || !region.HasEnumValues || !reflect.DeepEqual(region.EnumValues, []string{"eu", "us"}) || region.Description != "Data center" {
+		t.Errorf("region = %+v", region)
+	}
+	if version.HasEnumValues {
+		t.Errorf("version has enum values %v", version.EnumValues)
+	}
+	if local := servers[1]; local.HasVariables || local.URL != "http://localhost:8080" {
+		t.Errorf("local server = %+v", local)
+	}
+}
+
+func Test_GetOperations_servers(t *testing.T) {
+	p := loadTestSpec(t, nil, `
+openapi: 3.0.3
+info:
+	title: Servers
+	version: "1"
+servers:
+	- url: https://api.example.com
+paths:
+	/pets:
+		servers:
+			- url: https://pets.example.com
+		get:
+			operationId: listPets
+			responses:
+				"200":
+					description: ok
+		post:
+			operationId: addPet
+			servers:
+				- url: https://{env}.example.com
+					variables:
+						env:
+							default: write
+			responses:
+				"201":
+					description: created
+	/users:
+		get:
+			operationId: listUsers
+			responses:
+				"200":
+					description: ok
+`)
+	byTag, err := p.GetOperations()
+	if err != nil {
+		t.Fatalf("GetOperations: %v", err)
+	}
+
+	want := map[string]string{
+		"listPets":  "https://pets.example.com",
+		"addPet":    "https://{env}.example.com",
+		"listUsers": "",
+	}
+	for _, op := range byTag[defaultTag] {
+		var got string
+		if len(op.Servers) > 0 {
+			got = op.Servers[0].URL
+		}
+		if got != want[op.OperationId] {
+			t.Errorf("%s server = %q, want %q", op.OperationId, got, want[op.OperationId])
+		}
+	}
+}
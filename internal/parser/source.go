@@ -0,0 +1,523 @@
+package parser
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/xseman/openapi-generator/internal/codegen"
+	"gopkg.in/yaml.v3"
+)
+
+// Degradation is a construct of the spec the generated code does not represent
+// exactly, such as a schema generated as any.
+type Degradation struct {
+	Source  *codegen.Source
+	Message string
+}
+
+// String formats the degradation with its location.
+func (d Degradation) String() string {
+	return d.Source.String() + ": " + d.Message
+}
+
+// degrade records a degradation, once per location and message.
+func (p *Parser) degrade(source *codegen.Source, format string, args ...any) {
+	if p.silent {
+		return
+	}
+	d := Degradation{Source: source, Message: fmt.Sprintf(format, args...)}
+	for _, existing := range p.Degradations {
+		if existing.Message == d.Message && existing.Source.String() == d.Source.String() {
+			return
+		}
+	}
+	p.Degradations = append(p.Degradations, d)
+}
+
+// degradeToAny records a schema generated as the any type, unless the schema
+// describes no type at all.
+func (p *Parser) degradeToAny(source *codegen.Source, schema *openapi3.Schema) {
+	if description := lostSchema(schema); description != "" {
+		p.degrade(source, "%s is generated as %s", description, p.anyType())
+	}
+}
+
+// lostSchema describes what a schema generated as the any type declared, or
+// returns an empty string when it declared nothing.
+func lostSchema(schema *openapi3.Schema) string {
+	if schema == nil {
+		return ""
+	}
+	if schema.Type != nil && len(schema.Type.Slice()) > 0 {
+		schemaType := schema.Type.Slice()[0]
+		switch schemaType {
+		case "array", "object", "string", "integer", "number", "boolean":
+		default:
+			return fmt.Sprintf("schema of unknown type %q", schemaType)
+		}
+	}
+	switch {
+	case len(schema.OneOf) > 0:
+		return "inline oneOf schema"
+	case len(schema.AnyOf) > 0:
+		return "inline anyOf schema"
+	case len(schema.AllOf) > 0:
+		return "inline allOf schema"
+	case len(schema.Properties) > 0:
+		return "inline object schema"
+	case schema.Items != nil:
+		return "inline array schema"
+	}
+	return ""
+}
+
+// setDoc replaces the loaded document. The location is the file or URL the
+// document was read from, empty when it was read from data or merged. The
+// positions locate its elements, nil when origins are not tracked.
+func (p *Parser) setDoc(doc *openapi3.T, location string, positions positionIndex) {
+	p.Doc = doc
+	p.specLocation = location
+	p.swagger2 = false
+	p.positions = positions
+	p.sources = nil
+}
+
+// positionIndex holds the line and column of the elements of the loaded
+// documents, by location and JSON pointer. Object members are located by their
+// key and array items by their first key.
+type positionIndex map[string]map[string]openapi3.Location
+
+// newPositions returns an empty index when TrackOrigins is set, or else nil.
+func (p *Parser) newPositions() positionIndex {
+	if !p.TrackOrigins {
+		return nil
+	}
+	return make(positionIndex)
+}
+
+// record locates the elements of a document read from a location, once. The
+// positions of an overlaid document are given, the others are read from data.
+// Recording into a nil index does nothing.
+func (index positionIndex) record(location string, data []byte, overlaid map[string]openapi3.Location) {
+	if _, ok := index[positionKey(location)]; ok || index == nil {
+		return
+	}
+	if overlaid == nil {
+		var root yaml.Node
+		if yaml.Unmarshal(data, &root) != nil {
+			return
+		}
+		overlaid = nodePositions(&root)
+	}
+	index[positionKey(location)] = overlaid
+}
+
+// lookup returns the position of an element of a document.
+func (index positionIndex) lookup(location, pointer string) (openapi3.Location, bool) {
+	position, ok := index[positionKey(location)][pointer]
+	return position, ok
+}
+
+// positionKey identifies a location, so a file is found however its path is written.
+func positionKey(location string) string {
+	if location == "" || strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		return location
+	}
+	if abs, err := filepath.Abs(location); err == nil {
+		return abs
+	}
+	return location
+}
+
+// nodePositions locates the elements of a YAML or JSON document by JSON pointer.
+func nodePositions(root *yaml.Node) map[string]openapi3.Location {
+	positions := make(map[string]openapi3.Location)
+	inProgress := make(map[*yaml.Node]bool)
+
+	var walk func(node *yaml.Node, pointer string)
+	walk = func(node *yaml.Node, pointer string) {
+		for node.Kind == yaml.DocumentNode || node.Kind == yaml.AliasNode {
+			if node.Kind == yaml.AliasNode {
+				node = node.Alias
+			} else if len(node.Content) > 0 {
+				node = node.Content[0]
+			} else {
+				return
+			}
+		}
+		// Aliases may repeat a node, but not nest it in itself
+		if inProgress[node] {
+			return
+		}
+		inProgress[node] = true
+		defer delete(inProgress, node)
+
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key := node.Content[i]
+				memberPointer := pointer + "/" + escapePointer(key.Value)
+				positions[memberPointer] = openapi3.Location{Line: key.Line, Column: key.Column}
+				walk(node.Content[i+1], memberPointer)
+			}
+		case yaml.SequenceNode:
+			for i, item := range node.Content {
+				itemPointer := fmt.Sprintf("%s/%d", pointer, i)
+				if item.Kind == yaml.MappingNode && len(item.Content) > 0 {
+					positions[itemPointer] = openapi3.Location{Line: item.Content[0].Line, Column: item.Content[0].Column}
+				}
+				walk(item, itemPointer)
+			}
+		}
+	}
+	walk(root, "#")
+	return positions
+}
+
+// swagger2Translations map the pointers of a document converted from Swagger
+// 2.0 to the pointers of the same elements in the Swagger document.
+var swagger2Translations = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`^#/components/schemas/`), "#/definitions/"},
+	{regexp.MustCompile(`^#/components/parameters/`), "#/parameters/"},
+	{regexp.MustCompile(`^#/components/responses/`), "#/responses/"},
+	{regexp.MustCompile(`(/responses/[^/]+)/content/[^/]+/schema`), "$1/schema"},
+	{regexp.MustCompile(`(/headers/[^/]+)/schema`), "$1"},
+}
+
+// swagger2Pointer translates a pointer of a document converted from Swagger
+// 2.0 to the Swagger document. Operation parameters and request bodies are
+// moved by the conversion, so their pointers are not translated.
+func swagger2Pointer(pointer string) (string, bool) {
+	if strings.Contains(pointer, "/requestBod") || (strings.HasPrefix(pointer, "#/paths/") && strings.Contains(pointer, "/parameters/")) {
+		return pointer, false
+	}
+	for _, translation := range swagger2Translations {
+		pointer = translation.pattern.ReplaceAllString(pointer, translation.replacement)
+	}
+	return pointer, true
+}
+
+// sourceOf returns the location of a schema, operation or parameter of the
+// loaded document, or nil when unknown.
+func (p *Parser) sourceOf(element any) *codegen.Source {
+	if p.Doc == nil {
+		return nil
+	}
+	if p.sources == nil {
+		p.sources = p.indexSources()
+	}
+	return p.sources[element]
+}
+
+// indexSources locates the schemas, operations and parameters of the document,
+// including those of callbacks and of header components.
+// Components are indexed first, so an element referenced from several places
+// is located where it is defined.
+func (p *Parser) indexSources() map[any]*codegen.Source {
+	index := make(map[any]*codegen.Source)
+	add := func(element any, file, pointer string) bool {
+		if _, ok := index[element]; ok {
+			return false
+		}
+		located := true
+		if p.swagger2 && file == p.specLocation {
+			pointer, located = swagger2Pointer(pointer)
+		}
+		source := &codegen.Source{File: file, Pointer: pointer}
+		if position, ok := p.positions.lookup(file, pointer); ok && located {
+			source.Line = position.Line
+			source.Column = position.Column
+		}
+		index[element] = source
+		return true
+	}
+
+	var walkSchema func(ref *openapi3.SchemaRef, file, pointer string)
+	walkSchema = func(ref *openapi3.SchemaRef, file, pointer string) {
+		if ref == nil || ref.Value == nil {
+			return
+		}
+		if ref.Ref != "" {
+			file, pointer = refLocation(file, ref.Ref)
+		}
+		schema := ref.Value
+		if !add(schema, file, pointer) {
+			return
+		}
+		for _, name := range sortedKeys(schema.Properties) {
+			walkSchema(schema.Properties[name], file, pointer+"/properties/"+escapePointer(name))
+		}
+		walkSchema(schema.Items, file, pointer+"/items")
+		walkSchema(schema.AdditionalProperties.Schema, file, pointer+"/additionalProperties")
+		for i, sub := range schema.AllOf {
+			walkSchema(sub, file, fmt.Sprintf("%s/allOf/%d", pointer, i))
+		}
+		for i, sub := range schema.OneOf {
+			walkSchema(sub, file, fmt.Sprintf("%s/oneOf/%d", pointer, i))
+		}
+		for i, sub := range schema.AnyOf {
+			walkSchema(sub, file, fmt.Sprintf("%s/anyOf/%d", pointer, i))
+		}
+		walkSchema(schema.Not, file, pointer+"/not")
+	}
+
+	walkContent := func(content openapi3.Content, file, pointer string) {
+		for _, mediaType := range sortedKeys(content) {
+			if content[mediaType] != nil {
+				walkSchema(content[mediaType].Schema, file, pointer+"/content/"+escapePointer(mediaType)+"/schema")
+			}
+		}
+	}
+
+	walkParameter := func(ref *openapi3.ParameterRef, file, pointer string) {
+		if ref == nil || ref.Value == nil {
+			return
+		}
+		if ref.Ref != "" {
+			file, pointer = refLocation(file, ref.Ref)
+		}
+		param := ref.Value
+		if add(param, file, pointer) {
+			walkSchema(param.Schema, file, pointer+"/schema")
+			walkContent(param.Content, file, pointer)
+		}
+	}
+
+	walkHeader := func(ref *openapi3.HeaderRef, file, pointer string) {
+		if ref == nil || ref.Value == nil {
+			return
+		}
+		if ref.Ref != "" {
+			file, pointer = refLocation(file, ref.Ref)
+		}
+		walkSchema(ref.Value.Schema, file, pointer+"/schema")
+		walkContent(ref.Value.Content, file, pointer)
+	}
+
+	walkRequestBody := func(ref *openapi3.RequestBodyRef, file, pointer string) {
+		if ref == nil || ref.Value == nil {
+			return
+		}
+		if ref.Ref != "" {
+			file, pointer = refLocation(file, ref.Ref)
+		}
+		walkContent(ref.Value.Content, file, pointer)
+	}
+
+	walkResponse := func(ref *openapi3.ResponseRef, file, pointer string) {
+		if ref == nil || ref.Value == nil {
+			return
+		}
+		if ref.Ref != "" {
+			file, pointer = refLocation(file, ref.Ref)
+		}
+		walkContent(ref.Value.Content, file, pointer)
+		for _, name := range sortedKeys(ref.Value.Headers) {
+			walkHeader(ref.Value.Headers[name], file, pointer+"/headers/"+escapePointer(name))
+		}
+	}
+
+	var walkCallback func(ref *openapi3.CallbackRef, file, pointer string)
+	walkPathItem := func(item *openapi3.PathItem, file, pointer string) {
+		if item == nil {
+			return
+		}
+		if item.Ref != "" {
+			file, pointer = refLocation(file, item.Ref)
+		}
+		for i, param := range item.Parameters {
+			walkParameter(param, file, fmt.Sprintf("%s/parameters/%d", pointer, i))
+		}
+		for _, method := range httpMethods {
+			op := item.GetOperation(method)
+			if op == nil {
+				continue
+			}
+			opPointer := pointer + "/" + strings.ToLower(method)
+			if !add(op, file, opPointer) {
+				continue
+			}
+			for i, param := range op.Parameters {
+				walkParameter(param, file, fmt.Sprintf("%s/parameters/%d", opPointer, i))
+			}
+			walkRequestBody(op.RequestBody, file, opPointer+"/requestBody")
+			if op.Responses != nil {
+				responses := op.Responses.Map()
+				for _, code := range sortedKeys(responses) {
+					walkResponse(responses[code], file, opPointer+"/responses/"+escapePointer(code))
+				}
+			}
+			for _, name := range sortedKeys(op.Callbacks) {
+				walkCallback(op.Callbacks[name], file, opPointer+"/callbacks/"+escapePointer(name))
+			}
+		}
+	}
+
+	walkCallback = func(ref *openapi3.CallbackRef, file, pointer string) {
+		if ref == nil || ref.Value == nil {
+			return
+		}
+		if ref.Ref != "" {
+			file, pointer = refLocation(file, ref.Ref)
+		}
+		items := ref.Value.Map()
+		for _, expression := range sortedKeys(items) {
+			walkPathItem(items[expression], file, pointer+"/"+escapePointer(expression))
+		}
+	}
+
+	file := p.specLocation
+	if components := p.Doc.Components; components != nil {
+		for _, name := range sortedKeys(components.Schemas) {
+			walkSchema(components.Schemas[name], file, "#/components/schemas/"+escapePointer(name))
+		}
+		for _, name := range sortedKeys(components.Parameters) {
+			walkParameter(components.Parameters[name], file, "#/components/parameters/"+escapePointer(name))
+		}
+		for _, name := range sortedKeys(components.Headers) {
+			walkHeader(components.Headers[name], file, "#/components/headers/"+escapePointer(name))
+		}
+		for _, name := range sortedKeys(components.RequestBodies) {
+			walkRequestBody(components.RequestBodies[name], file, "#/components/requestBodies/"+escapePointer(name))
+		}
+		for _, name := range sortedKeys(components.Responses) {
+			walkResponse(components.Responses[name], file, "#/components/responses/"+escapePointer(name))
+		}
+		for _, name := range sortedKeys(components.Callbacks) {
+			walkCallback(components.Callbacks[name], file, "#/components/callbacks/"+escapePointer(name))
+		}
+	}
+
+	if p.Doc.Paths != nil {
+		paths := p.Doc.Paths.Map()
+		for _, path := range sortedKeys(paths) {
+			walkPathItem(paths[path], file, "#/paths/"+escapePointer(path))
+		}
+	}
+	return index
+}
+
+// locateLoadError adds the location of the $ref a loader error is about, found
+// in the raw spec: the reference the error names, or else the first local
+// reference that does not resolve. Other errors are returned unchanged.
+func locateLoadError(data []byte, file string, err error) error {
+	var root yaml.Node
+	if yaml.Unmarshal(data, &root) != nil {
+		return err
+	}
+	var refs []*yaml.Node
+	var collect func(node *yaml.Node)
+	collect = func(node *yaml.Node) {
+		if node.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == "$ref" && node.Content[i+1].Kind == yaml.ScalarNode {
+					refs = append(refs, node.Content[i+1])
+				}
+			}
+		}
+		for _, child := range node.Content {
+			collect(child)
+		}
+	}
+	collect(&root)
+
+	located := func(ref *yaml.Node, format string, args ...any) error {
+		location := fmt.Sprintf("line %d, column %d", ref.Line, ref.Column)
+		if file != "" {
+			location = fmt.Sprintf("%s:%d:%d", file, ref.Line, ref.Column)
+		}
+		return fmt.Errorf("%s: "+format, append([]any{location}, args...)...)
+	}
+	message := err.Error()
+	for _, ref := range refs {
+		if strings.Contains(message, fmt.Sprintf("%q", ref.Value)) {
+			return located(ref, "%w", err)
+		}
+	}
+	for _, ref := range refs {
+		if strings.HasPrefix(ref.Value, "#") && resolveNodePointer(&root, ref.Value) == nil {
+			return located(ref, "$ref %q: %w", ref.Value, err)
+		}
+	}
+	return err
+}
+
+// resolveNodePointer returns the node a local JSON pointer such as
+// "#/components/schemas/Pet" selects, or nil when it selects nothing.
+func resolveNodePointer(root *yaml.Node, pointer string) *yaml.Node {
+	node := root
+	tokens := strings.Split(strings.TrimPrefix(pointer, "#"), "/")[1:]
+	for {
+		switch node.Kind {
+		case yaml.DocumentNode:
+			if len(node.Content) == 0 {
+				return nil
+			}
+			node = node.Content[0]
+			continue
+		case yaml.AliasNode:
+			node = node.Alias
+			continue
+		}
+		if len(tokens) == 0 {
+			return node
+		}
+		token := strings.ReplaceAll(strings.ReplaceAll(tokens[0], "~1", "/"), "~0", "~")
+		tokens = tokens[1:]
+
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == token {
+					next = node.Content[i+1]
+				}
+			}
+		case yaml.SequenceNode:
+			if index, err := strconv.Atoi(token); err == nil && index >= 0 && index < len(node.Content) {
+				next = node.Content[index]
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+}
+
+// refLocation returns the file and pointer a reference made in file points to.
+func refLocation(file, ref string) (string, string) {
+	refFile, fragment, _ := strings.Cut(ref, "#")
+	pointer := "#" + fragment
+	switch {
+	case refFile == "":
+		return file, pointer
+	case strings.HasPrefix(file, "http://") || strings.HasPrefix(file, "https://"):
+		base, err := url.Parse(file)
+		if err != nil {
+			return refFile, pointer
+		}
+		target, err := url.Parse(refFile)
+		if err != nil {
+			return refFile, pointer
+		}
+		return base.ResolveReference(target).String(), pointer
+	case file == "" || filepath.IsAbs(refFile) || strings.Contains(refFile, "://"):
+		return refFile, pointer
+	default:
+		return filepath.Join(filepath.Dir(file), refFile), pointer
+	}
+}
+
+// escapePointer escapes a JSON pointer token.
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
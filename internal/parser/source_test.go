@@ -0,0 +1,374 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sourceSpec = `openapi: 3.0.3
+info:
+  title: Sources
+  version: "1"
+servers:
+  - url: https://{region}.example.com
+    variables:
+      region:
+        default: eu
+paths:
+  /pets:
+    parameters:
+      - name: trace
+        in: header
+        schema:
+          type: string
+    post:
+      operationId: addPet
+      responses:
+        "201":
+          description: created
+          links:
+            GetPet:
+              operationId: getPet
+              parameters:
+                petId: $response.body#/id
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ok
+components:
+  schemas:
+    Pet:
+      type: object
+      x-meta:
+        owner: team
+      example:
+        name: Rex
+      properties:
+        name:
+          type: string
+        tags:
+          type: object
+          default:
+            color: brown
+          additionalProperties:
+            type: string
+`
+
+// writeTestFile writes a file to a temporary directory and returns its path.
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestTrackOriginsKeepsMaps(t *testing.T) {
+	p := NewParser()
+	p.TrackOrigins = true
+	if err := p.LoadFromFile(writeTestFile(t, "spec.yaml", sourceSpec)); err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+
+	pet := p.Doc.Components.Schemas["Pet"].Value
+	maps := map[string]map[string]any{
+		"link parameters": p.Doc.Paths.Value("/pets").Post.Responses.Status(201).Value.Links["GetPet"].Value.Parameters,
+		"extension":       pet.Extensions["x-meta"].(map[string]any),
+		"example":         pet.Example.(map[string]any),
+		"default":         pet.Properties["tags"].Value.Default.(map[string]any),
+	}
+	for name, values := range maps {
+		if len(values) != 1 {
+			t.Errorf("%s = %v, want a single entry", name, values)
+		}
+	}
+	if variables := p.Doc.Servers[0].Variables; len(variables) != 1 {
+		t.Errorf("server variables = %v", variables)
+	}
+
+	byTag, err := p.GetOperations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, op := range byTag[defaultTag] {
+		for _, resp := range op.Responses {
+			for _, link := range resp.Links {
+				if len(link.Parameters) != 1 {
+					t.Errorf("link %s parameters = %v", link.Name, link.Parameters)
+				}
+			}
+		}
+	}
+}
+
+func TestOverlayKeepsSources(t *testing.T) {
+	load := func(overlays ...string) *Parser {
+		t.Helper()
+		p := NewParser()
+		p.TrackOrigins = true
+		for _, overlay := range overlays {
+			loaded, err := LoadOverlay(writeTestFile(t, "overlay.yaml", overlay))
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.Overlays = append(p.Overlays, loaded)
+		}
+		if err := p.LoadFromFile(writeTestFile(t, "spec.yaml", sourceSpec)); err != nil {
+			t.Fatalf("LoadFromFile: %v", err)
+		}
+		return p
+	}
+	plain := load()
+	overlaid := load(`overlay: 1.0.0
+info:
+  title: Patch
+  version: "1"
+actions:
+  - target: $.paths['/pets/{petId}'].get
+    update:
+      summary: Find a pet
+  - target: $.components.schemas.Pet.properties
+    update:
+      age:
+        type: integer
+  - target: $.paths['/pets'].parameters[0]
+    remove: true
+`)
+
+	source := func(p *Parser, element any) string {
+		return strings.TrimPrefix(p.sourceOf(element).String(), filepath.Dir(p.specLocation))
+	}
+
+	checks := []struct {
+		name        string
+		plain, over any
+	}{
+		{"schema", plain.Doc.Components.Schemas["Pet"].Value, overlaid.Doc.Components.Schemas["Pet"].Value},
+		{"property", plain.Doc.Components.Schemas["Pet"].Value.Properties["tags"].Value, overlaid.Doc.Components.Schemas["Pet"].Value.Properties["tags"].Value},
+		{"operation", plain.Doc.Paths.Value("/pets/{petId}").Get, overlaid.Doc.Paths.Value("/pets/{petId}").Get},
+		{"parameter", plain.Doc.Paths.Value("/pets/{petId}").Get.Parameters[0].Value, overlaid.Doc.Paths.Value("/pets/{petId}").Get.Parameters[0].Value},
+	}
+	for _, check := range checks {
+		want, got := source(plain, check.plain), source(overlaid, check.over)
+		if got != want {
+			t.Errorf("%s: overlaid source = %s, want %s", check.name, got, want)
+		}
+		if !strings.Contains(got, ".yaml:") {
+			t.Errorf("%s: source %s has no line", check.name, got)
+		}
+	}
+
+	added := overlaid.sourceOf(overlaid.Doc.Components.Schemas["Pet"].Value.Properties["age"].Value)
+	if added == nil || added.Line != 0 {
+		t.Errorf("property added by the overlay is located at %v", added)
+	}
+}
+
+func TestLoadErrorLocation(t *testing.T) {
+	tests := []struct {
+		name, ref, want string
+	}{
+		{"missing section", "#/components/schemas/Missing", `spec.yaml:11:55: $ref "#/components/schemas/Missing": `},
+		{"missing file", "./missing.yaml#/Pet", `spec.yaml:11:55: error resolving reference "./missing.yaml#/Pet"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := `openapi: 3.0.3
+info:
+  title: Errors
+  version: "1"
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: ok
+          content: {application/json: {schema: {$ref: "` + tt.ref + `"}}}
+`
+			err := NewParser().LoadFromFile(writeTestFile(t, "spec.yaml", spec))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %s", err, tt.want)
+			}
+		})
+	}
+}
+
+// trackedParser loads a spec file with TrackOrigins set.
+func trackedParser(t *testing.T, path string) *Parser {
+	t.Helper()
+	p := NewParser()
+	p.TrackOrigins = true
+	if err := p.LoadFromFile(path); err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	return p
+}
+
+// checkSource checks the location of an element of the loaded document.
+func checkSource(t *testing.T, p *Parser, name string, element any, want string) {
+	t.Helper()
+	source := p.sourceOf(element)
+	if source == nil {
+		t.Errorf("%s has no source", name)
+		return
+	}
+	got := strings.TrimPrefix(source.String(), filepath.Dir(p.specLocation)+string(filepath.Separator))
+	if got != want {
+		t.Errorf("%s source = %s, want %s", name, got, want)
+	}
+}
+
+func Test_LoadFromFile_emptySecurityRequirement(t *testing.T) {
+	p := trackedParser(t, writeTestFile(t, "spec.yaml", `openapi: 3.0.3
+info:
+  title: Anonymous
+  version: "1"
+security:
+  - {}
+paths:
+  /pets:
+    get:
+      security: [{}]
+      responses:
+        "200":
+          description: ok
+`))
+	op := p.Doc.Paths.Value("/pets").Get
+	if op.Security == nil || len(*op.Security) != 1 || len((*op.Security)[0]) != 0 {
+		t.Errorf("operation security = %v, want one empty requirement", op.Security)
+	}
+	checkSource(t, p, "operation", op, "spec.yaml:9:5 #/paths/~1pets/get")
+}
+
+func Test_sourceOf_callbacksAndHeaders(t *testing.T) {
+	p := trackedParser(t, writeTestFile(t, "spec.yaml", `openapi: 3.0.3
+info:
+  title: Callbacks
+  version: "1"
+paths:
+  /subscribe:
+    post:
+      responses:
+        "201":
+          description: created
+          headers:
+            X-Rate:
+              $ref: '#/components/headers/Rate'
+      callbacks:
+        onEvent:
+          '{$request.body#/url}':
+            post:
+              requestBody:
+                content:
+                  application/json:
+                    schema:
+                      type: object
+              responses:
+                "200":
+                  description: ok
+components:
+  headers:
+    Rate:
+      schema:
+        type: integer
+`))
+	op := p.Doc.Paths.Value("/subscribe").Post
+	callback := op.Callbacks["onEvent"].Value.Value("{$request.body#/url}").Post
+	checkSource(t, p, "callback operation", callback, "spec.yaml:17:13 #/paths/~1subscribe/post/callbacks/onEvent/{$request.body#~1url}/post")
+	checkSource(t, p, "callback body", callback.RequestBody.Value.Content["application/json"].Schema.Value,
+		"spec.yaml:21:21 #/paths/~1subscribe/post/callbacks/onEvent/{$request.body#~1url}/post/requestBody/content/application~1json/schema")
+	header := op.Responses.Status(201).Value.Headers["X-Rate"].Value
+	checkSource(t, p, "header schema", header.Schema.Value, "spec.yaml:29:7 #/components/headers/Rate/schema")
+}
+
+func Test_sourceOf_externalFile(t *testing.T) {
+	dir := t.TempDir()
+	common := "components:\n  schemas:\n    Error:\n      type: object\n      properties:\n        message:\n          type: string\n"
+	if err := os.WriteFile(filepath.Join(dir, "common.yaml"), []byte(common), 0600); err != nil {
+		t.Fatal(err)
+	}
+	spec := filepath.Join(dir, "spec.yaml")
+	if err := os.WriteFile(spec, []byte(`openapi: 3.0.3
+info:
+  title: External
+  version: "1"
+paths:
+  /pets:
+    get:
+      responses:
+        default:
+          description: error
+          content:
+            application/json:
+              schema:
+                $ref: common.yaml#/components/schemas/Error
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	p := trackedParser(t, spec)
+	schema := p.Doc.Paths.Value("/pets").Get.Responses.Default().Value.Content["application/json"].Schema.Value
+	checkSource(t, p, "external schema", schema, "common.yaml:3:5 #/components/schemas/Error")
+	checkSource(t, p, "external property", schema.Properties["message"].Value, "common.yaml:6:9 #/components/schemas/Error/properties/message")
+}
+
+func Test_sourceOf_swagger2(t *testing.T) {
+	p := trackedParser(t, writeTestFile(t, "swagger.yaml", `swagger: "2.0"
+info:
+  title: Legacy
+  version: "1"
+paths:
+  /pets:
+    get:
+      produces: [application/json]
+      responses:
+        "200":
+          description: ok
+          schema:
+            type: object
+definitions:
+  Pet:
+    type: object
+    properties:
+      name:
+        type: string
+`))
+	pet := p.Doc.Components.Schemas["Pet"].Value
+	checkSource(t, p, "definition", pet, "swagger.yaml:15:3 #/definitions/Pet")
+	checkSource(t, p, "property", pet.Properties["name"].Value, "swagger.yaml:18:7 #/definitions/Pet/properties/name")
+	op := p.Doc.Paths.Value("/pets").Get
+	checkSource(t, p, "operation", op, "swagger.yaml:7:5 #/paths/~1pets/get")
+	checkSource(t, p, "response schema", op.Responses.Status(200).Value.Content["application/json"].Schema.Value,
+		"swagger.yaml:12:11 #/paths/~1pets/get/responses/200/schema")
+}
+
+func Test_sourceOf_data(t *testing.T) {
+	p := NewParser()
+	p.TrackOrigins = true
+	loadTestSpec(t, p, `openapi: 3.0.3
+info:
+	title: Data
+	version: "1"
+paths: {}
+components:
+	schemas:
+		Pet:
+			type: object
+`)
+	checkSource(t, p, "schema", p.Doc.Components.Schemas["Pet"].Value, "line 8, column 5 #/components/schemas/Pet")
+
+	untracked := NewParser()
+	loadTestSpec(t, untracked, "openapi: 3.0.3\ninfo:\n\ttitle: Data\n\tversion: \"1\"\npaths: {}\ncomponents:\n\tschemas:\n\t\tPet:\n\t\t\ttype: object\n")
+	checkSource(t, untracked, "untracked schema", untracked.Doc.Components.Schemas["Pet"].Value, "#/components/schemas/Pet")
+}
@@ -0,0 +1,168 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/xseman/openapi-generator/internal/codegen"
+)
+
+// TagStrategy selects the API classes an operation is generated into.
+type TagStrategy string
+
+const (
+	// TagStrategyFirst groups every operation under its first tag
+	TagStrategyFirst TagStrategy = "first"
+	// TagStrategyAll duplicates every operation into the API of each of its tags
+	TagStrategyAll TagStrategy = "all"
+	// TagStrategySingle groups all operations into a single API
+	TagStrategySingle TagStrategy = "single"
+)
+
+// defaultTag is the tag of operations without tags, and of all operations with TagStrategySingle.
+const defaultTag = "default"
+
+// ParseTagStrategy converts a strategy name, an empty name selecting TagStrategyFirst.
+func ParseTagStrategy(name string) (TagStrategy, error) {
+	switch strategy := TagStrategy(name); strategy {
+	case "":
+		return TagStrategyFirst, nil
+	case TagStrategyFirst, TagStrategyAll, TagStrategySingle:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown tag strategy %q (expected first, all or single)", name)
+	}
+}
+
+// operationTags returns the tags of the API classes an operation is generated into.
+func (p *Parser) operationTags(op *openapi3.Operation) []string {
+	if len(op.Tags) == 0 || p.TagStrategy == TagStrategySingle {
+		return []string{defaultTag}
+	}
+	if p.TagStrategy != TagStrategyAll {
+		return op.Tags[:1]
+	}
+
+	tags := make([]string, 0, len(op.Tags))
+	seen := make(map[string]bool, len(op.Tags))
+	for _, tag := range op.Tags {
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// dedupOperationIds renames the operations sharing an operationId within an API
+// by appending a counter, as their methods would clash. It returns a warning for
+// every renamed operation.
+func dedupOperationIds(operationsByTag map[string][]*codegen.CodegenOperation) []string {
+	tags := make([]string, 0, len(operationsByTag))
+	for tag := range operationsByTag {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	var warnings []string
+	for _, tag := range tags {
+		counts := make(map[string]int)
+		for _, op := range operationsByTag[tag] {
+			opID := op.OperationId
+			count, exists := counts[opID]
+			if !exists {
+				counts[opID] = 0
+				continue
+			}
+
+			newID := fmt.Sprintf("%s%d", opID, count+1)
+			warnings = append(warnings, fmt.Sprintf("Duplicate operation ID '%s' in tag '%s', renaming to '%s'", opID, tag, newID))
+			op.OperationId = newID
+			op.Nickname = newID
+			counts[opID] = count + 1
+		}
+	}
+	return warnings
+}
+
+// tagsToCodegen returns the name and description of the tags of an operation.
+func (p *Parser) tagsToCodegen(tags []string) []map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	result := make([]map[string]string, 0, len(tags))
+	for _, tag := range tags {
+		result = append(result, map[string]string{
+			"name":        tag,
+			"description": p.TagDescription(tag),
+		})
+	}
+	return result
+}
+
+// TagDescription returns the description of a tag declared by the document.
+func (p *Parser) TagDescription(name string) string {
+	if p.Doc == nil {
+		return ""
+	}
+	if tag := p.Doc.Tags.Get(name); tag != nil {
+		return tag.Description
+	}
+	return ""
+}
+
+// cloneForTag returns a copy of an operation generated into the API of tag.
+// Generators post-process the operations of each API, so the copy gets its own
+// parameters, and its callback requests are moved to the same API.
+func cloneForTag(op *codegen.CodegenOperation, tag string) *codegen.CodegenOperation {
+	clone := *op
+	clone.BaseName = tag
+
+	params := make(map[*codegen.CodegenParameter]*codegen.CodegenParameter)
+	cloneParam := func(param *codegen.CodegenParameter) *codegen.CodegenParameter {
+		if param == nil {
+			return nil
+		}
+		if copied, ok := params[param]; ok {
+			return copied
+		}
+		copied := *param
+		params[param] = &copied
+		return &copied
+	}
+	cloneParams := func(list []*codegen.CodegenParameter) []*codegen.CodegenParameter {
+		if list == nil {
+			return nil
+		}
+		copied := make([]*codegen.CodegenParameter, len(list))
+		for i, param := range list {
+			copied[i] = cloneParam(param)
+		}
+		return copied
+	}
+	clone.AllParams = cloneParams(op.AllParams)
+	clone.BodyParams = cloneParams(op.BodyParams)
+	clone.PathParams = cloneParams(op.PathParams)
+	clone.QueryParams = cloneParams(op.QueryParams)
+	clone.HeaderParams = cloneParams(op.HeaderParams)
+	clone.FormParams = cloneParams(op.FormParams)
+	clone.CookieParams = cloneParams(op.CookieParams)
+	clone.RequiredParams = cloneParams(op.RequiredParams)
+	clone.OptionalParams = cloneParams(op.OptionalParams)
+	clone.BodyParam = cloneParam(op.BodyParam)
+
+	if op.Callbacks != nil {
+		clone.Callbacks = make([]*codegen.CodegenCallback, len(op.Callbacks))
+		for i, cb := range op.Callbacks {
+			copied := *cb
+			copied.Operations = make([]*codegen.CodegenOperation, len(cb.Operations))
+			for j, request := range cb.Operations {
+				copied.Operations[j] = cloneForTag(request, tag)
+			}
+			clone.Callbacks[i] = &copied
+		}
+	}
+
+	return &clone
+}
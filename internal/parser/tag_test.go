@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/xseman/openapi-generator/internal/codegen"
+)
+
+func TestGetOperationsTagStrategyAll(t *testing.T) {
+	p := NewParser()
+	p.TagStrategy = TagStrategyAll
+	loadTestSpec(t, p, `
+openapi: 3.0.3
+info:
+	title: Tags
+	version: "1"
+paths:
+	/pets:
+		post:
+			operationId: createPet
+			tags: [pets, store]
+			parameters:
+				- name: status
+					in: query
+					schema:
+						type: string
+						enum: [available, sold]
+			callbacks:
+				onCreated:
+					"{$request.body#/callbackUrl}":
+						post:
+							responses:
+								"200":
+									description: ok
+			responses:
+				"201":
+					description: created
+`)
+
+	byTag, err := p.GetOperations()
+	if err != nil {
+		t.Fatalf("GetOperations: %v", err)
+	}
+	pets, store := byTag["pets"], byTag["store"]
+	if len(pets) != 1 || len(store) != 1 {
+		t.Fatalf("operations by tag = %v, want one under pets and store", byTag)
+	}
+
+	for tag, op := range map[string]*codegen.CodegenOperation{"pets": pets[0], "store": store[0]} {
+		if op.BaseName != tag {
+			t.Errorf("%s: BaseName = %q", tag, op.BaseName)
+		}
+		if got := op.Callbacks[0].Operations[0].BaseName; got != tag {
+			t.Errorf("%s: callback BaseName = %q", tag, got)
+		}
+		if op.AllParams[0] != op.QueryParams[0] {
+			t.Errorf("%s: AllParams and QueryParams no longer share the parameter", tag)
+		}
+	}
+	if pets[0].AllParams[0] == store[0].AllParams[0] {
+		t.Error("copies share their parameters")
+	}
+	if pets[0].Callbacks[0].Operations[0] == store[0].Callbacks[0].Operations[0] {
+		t.Error("copies share their callback requests")
+	}
+}
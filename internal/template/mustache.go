@@ -439,6 +439,21 @@ func PreprocessOperationData(opMaps []map[string]any) []map[string]any {
 		addHasArrayFlag(op, "produces", "hasProduces")
 		addHasArrayFlag(op, "consumes", "hasConsumes")
 		addHasArrayFlag(op, "authMethods", "hasAuthMethods")
+		addHasArrayFlag(op, "callbacks", "hasCallbacks")
+		addHasArrayFlag(op, "servers", "hasServers")
+
+		// Callback requests are rendered with the same partials as operations
+		if callbacks, ok := op["callbacks"].([]any); ok {
+			for _, cb := range callbacks {
+				cbMap, ok := cb.(map[string]any)
+				if !ok {
+					continue
+				}
+				if requests, ok := cbMap["operations"].([]any); ok {
+					PreprocessOperationData(toMapSlice(requests))
+				}
+			}
+		}
 	}
 	return opMaps
 }
@@ -457,6 +472,18 @@ func PreprocessModelData(modelMaps []map[string]any) []map[string]any {
 	return modelMaps
 }
 
+// toMapSlice returns the map elements of a generic slice.
+// The maps are shared with the input, so changes to them are visible in both.
+func toMapSlice(items []any) []map[string]any {
+	result := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		if m, ok := item.(map[string]any); ok {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
 // addHasArrayFlag adds a boolean flag indicating if the array has elements.
 func addHasArrayFlag(data map[string]any, arrayKey, flagKey string) {
 	if arr, ok := data[arrayKey]; ok && arr != nil {